@@ -0,0 +1,210 @@
+package cryptomkt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base32"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// ValidateAddress checks that address has the format of an address of currency, so typos are
+// caught before anything irreversible is sent to it. It returns an error wrapping
+// ErrInvalidAddress with the reason otherwise. BTC addresses can be base58 or bech32 and their
+// checksum is verified, ETH addresses are 0x and 40 hex digits, whose mixed case checksum isn't
+// verified, XLM addresses are public keys starting with G, with their checksum, and EOS addresses
+// are account names. Currencies without addresses, like the fiat ones, always fail.
+func ValidateAddress(currency WalletType, address string) error {
+	var reason string
+	switch currency {
+	case BTC:
+		if strings.HasPrefix(strings.ToLower(address), "bc1") {
+			reason = checkBech32(address, "bc")
+		} else {
+			reason = checkBase58Check(address)
+		}
+	case ETH:
+		if !ethAddress.MatchString(address) {
+			reason = "must be 0x followed by 40 hex digits"
+		}
+	case XLM:
+		reason = checkStellarKey(address)
+	case EOS:
+		if !eosAccount.MatchString(address) {
+			reason = "must be 1 to 12 characters from a-z, 1-5 and '.', not ending in '.'"
+		}
+	default:
+		reason = fmt.Sprintf("%s has no address format", currency)
+	}
+
+	if reason != "" {
+		return fmt.Errorf("%w %q: %s", ErrInvalidAddress, address, reason)
+	}
+	return nil
+}
+
+var (
+	ethAddress = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+	eosAccount = regexp.MustCompile(`^[a-z1-5.]{0,11}[a-z1-5]$`)
+)
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// checkBase58Check validates a legacy, P2PKH or P2SH, BTC address and its checksum
+func checkBase58Check(address string) string {
+	n := new(big.Int)
+	for _, r := range address {
+		i := strings.IndexRune(base58Alphabet, r)
+		if i < 0 {
+			return fmt.Sprintf("invalid base58 character %q", r)
+		}
+		n.Mul(n, big.NewInt(58))
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	// Every leading 1 stands for a leading zero byte
+	zeros := len(address) - len(strings.TrimLeft(address, "1"))
+	decoded := append(make([]byte, zeros), n.Bytes()...)
+	if len(decoded) != 25 {
+		return "wrong length"
+	}
+	if decoded[0] != 0x00 && decoded[0] != 0x05 {
+		return "unknown version, must start with 1 or 3"
+	}
+
+	first := sha256.Sum256(decoded[:21])
+	second := sha256.Sum256(first[:])
+	if !bytes.Equal(second[:4], decoded[21:]) {
+		return "checksum mismatch"
+	}
+	return ""
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// checkBech32 validates a segwit address with the human readable part hrp: its checksum, bech32 for
+// witness version 0 and bech32m for later ones as BIP 350 requires, and the length of its program
+func checkBech32(address, hrp string) string {
+	if strings.ToLower(address) != address && strings.ToUpper(address) != address {
+		return "mixes upper and lower case"
+	}
+	address = strings.ToLower(address)
+	if len(address) > 90 {
+		return "too long"
+	}
+
+	sep := strings.LastIndexByte(address, '1')
+	if sep < 1 || address[:sep] != hrp || len(address)-sep-1 < 7 {
+		return "wrong prefix or length"
+	}
+
+	values := make([]int, 0, len(hrp)*2+1+len(address)-sep-1)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, int(hrp[i]>>5))
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, int(hrp[i]&31))
+	}
+	for _, r := range address[sep+1:] {
+		i := strings.IndexRune(bech32Charset, r)
+		if i < 0 {
+			return fmt.Sprintf("invalid bech32 character %q", r)
+		}
+		values = append(values, i)
+	}
+
+	data := values[len(hrp)*2+1:]
+	witnessVersion := data[0]
+	if witnessVersion > 16 {
+		return fmt.Sprintf("invalid witness version %d", witnessVersion)
+	}
+	checksum := 0x2bc830a3
+	if witnessVersion == 0 {
+		checksum = 1
+	}
+	if bech32Polymod(values) != checksum {
+		return "checksum mismatch"
+	}
+
+	program, ok := bech32Program(data[1 : len(data)-6])
+	if !ok {
+		return "invalid witness program padding"
+	}
+	if len(program) < 2 || len(program) > 40 || witnessVersion == 0 && len(program) != 20 && len(program) != 32 {
+		return fmt.Sprintf("invalid witness program length %d for version %d", len(program), witnessVersion)
+	}
+	return ""
+}
+
+// bech32Program regroups the 5-bit values of a witness program into bytes, which only allows the
+// zero padding of less than 5 bits left over by the encoder
+func bech32Program(values []int) ([]byte, bool) {
+	var program []byte
+	acc, bits := 0, uint(0)
+	for _, v := range values {
+		acc = acc<<5 | v
+		bits += 5
+		for bits >= 8 {
+			bits -= 8
+			program = append(program, byte(acc>>bits))
+		}
+		acc &= 1<<bits - 1
+	}
+	return program, bits < 5 && acc == 0
+}
+
+// bech32Polymod is the checksum function of BIP 173
+func bech32Polymod(values []int) int {
+	gen := []int{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := 1
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ v
+		for i, g := range gen {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= g
+			}
+		}
+	}
+	return chk
+}
+
+// checkStellarKey validates a Stellar public key: the version byte of account IDs, 32 bytes of
+// key and a CRC16 checksum, in base32
+func checkStellarKey(address string) string {
+	if len(address) != 56 || address[0] != 'G' {
+		return "must be 56 characters starting with G"
+	}
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(address)
+	if err != nil || len(decoded) != 35 {
+		return "invalid base32"
+	}
+	if decoded[0] != 6<<3 {
+		return "not an account ID"
+	}
+
+	crc := crc16XModem(decoded[:33])
+	if decoded[33] != byte(crc) || decoded[34] != byte(crc>>8) {
+		return "checksum mismatch"
+	}
+	return ""
+}
+
+// crc16XModem is the checksum of Stellar keys
+func crc16XModem(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
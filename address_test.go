@@ -0,0 +1,47 @@
+package cryptomkt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAddress(t *testing.T) {
+	tests := []struct {
+		currency WalletType
+		address  string
+		valid    bool
+	}{
+		{BTC, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", true},
+		{BTC, "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", true},
+		{BTC, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", false},
+		{BTC, "1A1zP1eP5QGefi2DMPTfTL5SLmv7Divf0a", false},
+		{BTC, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", true},
+		{BTC, "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", true},
+		{BTC, "bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", true},
+		{BTC, "bc1p5d7rjq7g6rdk2yhzks9smlaqtedr4dekq08ge8ztwac72sfr9rusxg3297", true},
+		{BTC, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", false},
+		{BTC, "bc1Qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", false},
+		// Version 0 with a bech32m checksum and version 1 with a bech32 one
+		{BTC, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", false},
+		{BTC, "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut", false},
+		// Witness programs of 16 bytes for version 0 and of 1 byte for version 1
+		{BTC, "BC1QR508D6QEJXTDG4Y5R3ZARVARYV98GJ9P", false},
+		{BTC, "bc1pw5dgrnzv", false},
+		{ETH, "0x52908400098527886E0F7030069857D2E4169EE7", true},
+		{ETH, "0x52908400098527886E0F7030069857D2E4169EE", false},
+		{XLM, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H", true},
+		{XLM, "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2G", false},
+		{EOS, "eosio.token", true},
+		{EOS, "eosio.token.", false},
+		{CLP, "anything", false},
+	}
+	for _, tt := range tests {
+		err := ValidateAddress(tt.currency, tt.address)
+		if tt.valid && err != nil {
+			t.Errorf("ValidateAddress(%s, %q) = %v, want nil", tt.currency, tt.address, err)
+		}
+		if !tt.valid && !errors.Is(err, ErrInvalidAddress) {
+			t.Errorf("ValidateAddress(%s, %q) = %v, want ErrInvalidAddress", tt.currency, tt.address, err)
+		}
+	}
+}
@@ -0,0 +1,461 @@
+package cryptomkt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EstimateExecution walks the book to find the average price of buying or selling quantity
+// units at market. Buying consumes the SELL side and selling consumes the BUY side, one page
+// at a time, until quantity is met or the book is exhausted; filled is the quantity that the
+// book could actually absorb.
+func (c Client) EstimateExecution(ctx context.Context, market Market, ot OrderType, quantity float64) (avgPrice float64, filled float64, err error) {
+	if quantity < 0 {
+		return 0, 0, errors.New("quantity can't be negative")
+	}
+
+	side := SELL
+	if ot == SELL {
+		side = BUY
+	}
+
+	var cost float64
+	it := c.IterateBook(ctx, market, side, 0, false)
+	for filled < quantity && it.Next() {
+		for _, o := range it.Orders() {
+			price, err := parseFloat(o.Price)
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid price %q: %s", o.Price, err)
+			}
+			amount, err := parseFloat(o.Amount)
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid amount %q: %s", o.Amount, err)
+			}
+
+			if filled+amount > quantity {
+				amount = quantity - filled
+			}
+			filled += amount
+			cost += amount * price
+			if filled >= quantity {
+				break
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return 0, 0, err
+	}
+
+	if filled == 0 {
+		return 0, 0, nil
+	}
+	return cost / filled, filled, nil
+}
+
+// FullBook returns every page of both sides of the book of market, bids sorted from the highest
+// to the lowest price and asks from the lowest to the highest. Both sides are fetched concurrently
+// to keep the gap between them small, but they are still separate requests and may be momentarily
+// inconsistent with each other.
+func (c Client) FullBook(ctx context.Context, market Market) (bids, asks []OrderBookOrder, err error) {
+	var bidsErr, asksErr error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		bids, bidsErr = c.bookSide(ctx, market, BUY)
+	}()
+	go func() {
+		defer wg.Done()
+		asks, asksErr = c.bookSide(ctx, market, SELL)
+	}()
+	wg.Wait()
+
+	if bidsErr != nil {
+		return nil, nil, bidsErr
+	}
+	if asksErr != nil {
+		return nil, nil, asksErr
+	}
+
+	if err = sortBook(bids, true); err != nil {
+		return nil, nil, err
+	}
+	if err = sortBook(asks, false); err != nil {
+		return nil, nil, err
+	}
+	return bids, asks, nil
+}
+
+// bookSide fetches every page of one side of the book of market
+func (c Client) bookSide(ctx context.Context, market Market, ot OrderType) ([]OrderBookOrder, error) {
+	var orders []OrderBookOrder
+	it := c.IterateBook(ctx, market, ot, 0, false)
+	for it.Next() {
+		orders = append(orders, it.Orders()...)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// sortBook sorts orders by price, descending if desc is set
+func sortBook(orders []OrderBookOrder, desc bool) error {
+	prices := make(map[string]float64, len(orders))
+	for _, o := range orders {
+		if _, ok := prices[o.Price]; ok {
+			continue
+		}
+		p, err := parseFloat(o.Price)
+		if err != nil {
+			return fmt.Errorf("invalid price %q: %s", o.Price, err)
+		}
+		prices[o.Price] = p
+	}
+
+	sort.SliceStable(orders, func(i, j int) bool {
+		if desc {
+			return prices[orders[i].Price] > prices[orders[j].Price]
+		}
+		return prices[orders[i].Price] < prices[orders[j].Price]
+	})
+	return nil
+}
+
+// BookImbalance returns the imbalance between the volume of the top levels of the bids and the
+// asks of market, (bids - asks) / (bids + asks), which ranges from -1 when there are only asks
+// to 1 when there are only bids. It returns ErrEmptyBook if both sides are empty.
+func (c Client) BookImbalance(ctx context.Context, market Market, levels int) (float64, error) {
+	bids, err := c.bookTop(ctx, market, BUY, levels)
+	if err != nil {
+		return 0, err
+	}
+	asks, err := c.bookTop(ctx, market, SELL, levels)
+	if err != nil {
+		return 0, err
+	}
+
+	bidVolume, err := bookVolume(bids)
+	if err != nil {
+		return 0, err
+	}
+	askVolume, err := bookVolume(asks)
+	if err != nil {
+		return 0, err
+	}
+
+	if bidVolume+askVolume == 0 {
+		return 0, ErrEmptyBook
+	}
+	return (bidVolume - askVolume) / (bidVolume + askVolume), nil
+}
+
+// bookTop fetches the first levels orders of one side of the book of market, best price first
+func (c Client) bookTop(ctx context.Context, market Market, ot OrderType, levels int) ([]OrderBookOrder, error) {
+	var orders []OrderBookOrder
+	it := c.IterateBook(ctx, market, ot, 0, false)
+	for len(orders) < levels && it.Next() {
+		orders = append(orders, it.Orders()...)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(orders) > levels {
+		orders = orders[:levels]
+	}
+	return orders, nil
+}
+
+// bookVolume adds up the amounts of orders
+func bookVolume(orders []OrderBookOrder) (float64, error) {
+	var volume float64
+	for _, o := range orders {
+		amount, err := parseFloat(o.Amount)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q: %s", o.Amount, err)
+		}
+		volume += amount
+	}
+	return volume, nil
+}
+
+// TopOfBook returns the best bid and the best ask of market, or ErrEmptyBook if a side has no orders
+func (c Client) TopOfBook(ctx context.Context, market Market) (bid, ask OrderBookOrder, err error) {
+	bids, err := c.bookTop(ctx, market, BUY, 1)
+	if err != nil {
+		return OrderBookOrder{}, OrderBookOrder{}, err
+	}
+	asks, err := c.bookTop(ctx, market, SELL, 1)
+	if err != nil {
+		return OrderBookOrder{}, OrderBookOrder{}, err
+	}
+
+	if len(bids) == 0 || len(asks) == 0 {
+		return OrderBookOrder{}, OrderBookOrder{}, ErrEmptyBook
+	}
+	return bids[0], asks[0], nil
+}
+
+// MidPrice returns the midpoint between the best bid and the best ask of market
+func (c Client) MidPrice(ctx context.Context, market Market) (float64, error) {
+	bid, ask, err := c.TopOfBook(ctx, market)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := bid.Parse()
+	if err != nil {
+		return 0, err
+	}
+	a, err := ask.Parse()
+	if err != nil {
+		return 0, err
+	}
+	return (b.Price + a.Price) / 2, nil
+}
+
+// MicroPrice returns the midpoint of market weighted by the size of the top of each side,
+// (bid * askSize + ask * bidSize) / (bidSize + askSize), which leans towards the side with less
+// volume, the one more likely to move
+func (c Client) MicroPrice(ctx context.Context, market Market) (float64, error) {
+	bid, ask, err := c.TopOfBook(ctx, market)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := bid.Parse()
+	if err != nil {
+		return 0, err
+	}
+	a, err := ask.Parse()
+	if err != nil {
+		return 0, err
+	}
+	if b.Amount+a.Amount == 0 {
+		return 0, ErrEmptyBook
+	}
+	return (b.Price*a.Amount + a.Price*b.Amount) / (b.Amount + a.Amount), nil
+}
+
+// BookIntegrity checks the top of the book of market: it's crossed when the best bid is above
+// the best ask, and locked when they are equal. Neither should happen in a healthy book.
+func (c Client) BookIntegrity(ctx context.Context, market Market) (crossed bool, locked bool, err error) {
+	bid, ask, err := c.TopOfBook(ctx, market)
+	if err != nil {
+		return false, false, err
+	}
+
+	b, err := bid.Parse()
+	if err != nil {
+		return false, false, err
+	}
+	a, err := ask.Parse()
+	if err != nil {
+		return false, false, err
+	}
+	return b.Price > a.Price, b.Price == a.Price, nil
+}
+
+// Snapshot is the state of a market captured by MarketSnapshot
+type Snapshot struct {
+	Market Market
+	Ticker Ticker
+	Bid    OrderBookOrder
+	Ask    OrderBookOrder
+	// CapturedAt is when the requests of the snapshot were sent
+	CapturedAt time.Time
+}
+
+// MarketSnapshot fetches the Ticker and the top of both sides of the book of market concurrently,
+// so they're as close in time as the API allows. It fails with ErrEmptyBook if a side is empty.
+func (c Client) MarketSnapshot(ctx context.Context, market Market) (*Snapshot, error) {
+	snap := &Snapshot{Market: market, CapturedAt: c.now()}
+
+	var ticker *Ticker
+	var bids, asks []OrderBookOrder
+	var tickerErr, bidsErr, asksErr error
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		ticker, tickerErr = c.TickerOne(ctx, market)
+	}()
+	go func() {
+		defer wg.Done()
+		bids, bidsErr = c.bookTop(ctx, market, BUY, 1)
+	}()
+	go func() {
+		defer wg.Done()
+		asks, asksErr = c.bookTop(ctx, market, SELL, 1)
+	}()
+	wg.Wait()
+
+	for _, err := range []error{tickerErr, bidsErr, asksErr} {
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(bids) == 0 || len(asks) == 0 {
+		return nil, ErrEmptyBook
+	}
+
+	snap.Ticker = *ticker
+	snap.Bid = bids[0]
+	snap.Ask = asks[0]
+	return snap, nil
+}
+
+// fillEstimateTrades is how many recent trades EstimateTimeToFill measures the traded volume over
+const fillEstimateTrades = 200
+
+// EstimateTimeToFill is a rough estimate of how long a resting order of type ot at price would wait
+// in market before being reached. The model is a queue: the volume ahead of the order, the orders of
+// its side of the book at the same price or better, is traded away at the rate takers traded through
+// the price over the last 200 trades, so the estimate is that volume divided by that rate. It assumes
+// the rate holds and ignores cancellations and new orders ahead, so it's a baseline to compare
+// passive and aggressive execution, not a forecast. It's 0 when nothing is ahead of the order, and
+// it fails when no recent trade reached the price, since the rate can't be measured.
+func (c Client) EstimateTimeToFill(ctx context.Context, market Market, ot OrderType, price float64) (time.Duration, error) {
+	ahead, err := c.volumeAhead(ctx, market, ot, price)
+	if err != nil {
+		return 0, err
+	}
+	if ahead == 0 {
+		return 0, nil
+	}
+
+	trades, err := c.RecentTrades(ctx, market, fillEstimateTrades)
+	if err != nil {
+		return 0, err
+	}
+	if len(trades) == 0 {
+		return 0, fmt.Errorf("no recent trades in %s to estimate the fill rate", market)
+	}
+
+	// A resting BUY is filled by SELL takers at its price or lower, and a SELL by BUY takers at its price or higher
+	taker := SELL
+	if ot == SELL {
+		taker = BUY
+	}
+	var volume float64
+	for _, t := range trades {
+		parsed, err := t.Parse()
+		if err != nil {
+			return 0, err
+		}
+		if parsed.MarketTaker != taker {
+			continue
+		}
+		if (ot == BUY && parsed.Price <= price) || (ot == SELL && parsed.Price >= price) {
+			volume += parsed.Amount
+		}
+	}
+
+	window := c.now().Sub(trades[len(trades)-1].Timestamp.Time)
+	if volume == 0 || window <= 0 {
+		return 0, fmt.Errorf("no recent trades in %s reached %g", market, price)
+	}
+	rate := volume / window.Seconds()
+	return time.Duration(ahead / rate * float64(time.Second)), nil
+}
+
+// volumeAhead adds up the orders of the ot side of the book of market at price or better, which
+// are walked best first until one is worse than price
+func (c Client) volumeAhead(ctx context.Context, market Market, ot OrderType, price float64) (float64, error) {
+	var volume float64
+	it := c.IterateBook(ctx, market, ot, 0, false)
+	for it.Next() {
+		for _, o := range it.Orders() {
+			parsed, err := o.Parse()
+			if err != nil {
+				return 0, err
+			}
+			if (ot == BUY && parsed.Price < price) || (ot == SELL && parsed.Price > price) {
+				return volume, nil
+			}
+			volume += parsed.Amount
+		}
+	}
+	if err := it.Err(); err != nil {
+		return 0, err
+	}
+	return volume, nil
+}
+
+// topOfBookWorkers bounds the markets fetched at once by AllTopOfBook
+const topOfBookWorkers = 4
+
+// BookTop is the best bid and the best ask of a market
+type BookTop struct {
+	Bid OrderBookOrder
+	Ask OrderBookOrder
+}
+
+// AllTopOfBook fetches the TopOfBook of every known Market concurrently, with a bounded number of
+// markets in flight, each request still waiting on the rate limiter. Markets that failed, like
+// those with an empty side, are left out of the result and reported together in a MarketErrors.
+func (c Client) AllTopOfBook(ctx context.Context) (map[Market]BookTop, error) {
+	markets := SortedMarkets()
+	tops := make(map[Market]BookTop, len(markets))
+	errs := make(MarketErrors)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan Market)
+	for i := 0; i < topOfBookWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for market := range queue {
+				bid, ask, err := c.TopOfBook(ctx, market)
+
+				mu.Lock()
+				if err != nil {
+					errs[market] = err
+				} else {
+					tops[market] = BookTop{Bid: bid, Ask: ask}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, market := range markets {
+		queue <- market
+	}
+	close(queue)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return tops, errs
+	}
+	return tops, nil
+}
+
+// LiquidityWithin returns the volume of each side of the book of market priced within bandPercent
+// of the midpoint: bids down to mid * (1 - bandPercent/100) and asks up to mid * (1 + bandPercent/100).
+// Each side is walked best first, page by page, until an order falls outside the band.
+func (c Client) LiquidityWithin(ctx context.Context, market Market, bandPercent float64) (bidVolume, askVolume float64, err error) {
+	if bandPercent < 0 {
+		return 0, 0, fmt.Errorf("band %g%% must not be negative", bandPercent)
+	}
+
+	mid, err := c.MidPrice(ctx, market)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	band := mid * bandPercent / 100
+	if bidVolume, err = c.volumeAhead(ctx, market, BUY, mid-band); err != nil {
+		return 0, 0, err
+	}
+	if askVolume, err = c.volumeAhead(ctx, market, SELL, mid+band); err != nil {
+		return 0, 0, err
+	}
+	return bidVolume, askVolume, nil
+}
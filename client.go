@@ -1,21 +1,38 @@
 package cryptomkt
 
 import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
 	"crypto/hmac"
 	"crypto/sha512"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const apiURL = "https://api.cryptomkt.com/"
 const version = "v1/"
-const limit = 100
+
+// Bounds of the number of items per page accepted by the API, maxLimit is used by default
+const (
+	minLimit = 20
+	maxLimit = 100
+)
+
+// defaultMaxResponseSize is the largest response body read when WithMaxResponseSize isn't set
+const defaultMaxResponseSize = 10 << 20
 
 // MarketAssetMapping simplifies the obtention of the asset of a market
 var MarketAssetMapping = map[Market]WalletType{
@@ -57,14 +74,102 @@ var MarketCurrencyMapping = map[Market]WalletType{
 	EOSEUR: EUR,
 }
 
-func NewClient(key, secret string, timeout time.Duration) *Client {
-	return &Client{
-		key:    key,
-		secret: secret,
-		client: &http.Client{
-			Timeout: timeout,
-		},
+// WalletPrecision holds the number of decimals used for the amounts of each currency
+var WalletPrecision = map[WalletType]int{
+	ARS: 2,
+	BRL: 2,
+	CLP: 0,
+	EUR: 2,
+	ETH: 8,
+	XLM: 7,
+	BTC: 8,
+	EOS: 4,
+}
+
+// defaultPrecision is used for currencies missing from WalletPrecision
+const defaultPrecision = 4
+
+// FormatAmount formats amount with the precision of the currency w
+func FormatAmount(w WalletType, amount float64) string {
+	precision, ok := WalletPrecision[w]
+	if !ok {
+		precision = defaultPrecision
+	}
+	return strconv.FormatFloat(amount, 'f', precision, 64)
+}
+
+// formatPrecision formats amount with precision decimals, or like FormatAmount if it's nil
+func formatPrecision(w WalletType, amount float64, precision *int) string {
+	return strconv.FormatFloat(amount, 'f', amountPrecision(w, precision), 64)
+}
+
+// amountPrecision returns precision if it's set, or the precision of the currency w
+func amountPrecision(w WalletType, precision *int) int {
+	if precision != nil {
+		return *precision
+	}
+	if p, ok := WalletPrecision[w]; ok {
+		return p
+	}
+	return defaultPrecision
+}
+
+// defaultPricePrecision is the number of decimals of order prices. WalletPrecision holds the
+// precision of amounts, not the tick size of prices, which goes below a cent on markets like XLMEUR.
+const defaultPricePrecision = 4
+
+// formatPrice formats the price of an order with precision decimals, or defaultPricePrecision if
+// it's nil
+func formatPrice(price float64, precision *int) string {
+	if precision == nil {
+		return strconv.FormatFloat(price, 'f', defaultPricePrecision, 64)
+	}
+	return strconv.FormatFloat(price, 'f', *precision, 64)
+}
+
+// NewClient returns a *Client for the given credentials whose requests time out after timeout,
+// configured with opts. It can't report errors, so WithValidateOnStart only takes effect through New.
+func NewClient(key, secret string, timeout time.Duration, opts ...Option) *Client {
+	return NewClientWithOptions(key, secret, append([]Option{WithTimeout(timeout)}, opts...)...)
+}
+
+// NewClientWithOptions returns a *Client for the given credentials configured only with opts,
+// sending its requests through its own http.Client, without a timeout, to the CryptoMKT API
+// unless WithHTTPClient or WithBaseURL say otherwise
+func NewClientWithOptions(key, secret string, opts ...Option) *Client {
+	c := &Client{
+		key:     key,
+		secret:  secret,
+		client:  &http.Client{},
+		baseURL: apiURL,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// endpoint returns the URL of the endpoint at path under the base URL of the Client
+func (c Client) endpoint(path string) string {
+	base := c.baseURL
+	if base == "" {
+		base = apiURL
+	}
+	return base + version + path
+}
+
+// New is like NewClient, but it returns an error if WithValidateOnStart is set and
+// the credentials are rejected by the API
+func New(key, secret string, timeout time.Duration, opts ...Option) (*Client, error) {
+	c := NewClient(key, secret, timeout, opts...)
+	if !c.validateOnStart {
+		return c, nil
+	}
+
+	if _, err := c.Balance(context.Background()); err != nil {
+		return nil, fmt.Errorf("validating credentials: %w", err)
 	}
+	return c, nil
 }
 
 func (c Client) formURL(initialURL string, paramsMap map[string]string) (string, error) {
@@ -82,39 +187,222 @@ func (c Client) formURL(initialURL string, paramsMap map[string]string) (string,
 	return baseURL.String(), nil
 }
 
+// buildSignaturePayload returns the string that is signed to authenticate a request: the
+// timestamp, the path of the endpoint, and the values of data sorted by their keys
+func buildSignaturePayload(timestamp int64, path string, data url.Values) string {
+	payload := strconv.FormatInt(timestamp, 10) + "/" + version + path
+
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, value := range data[k] {
+			payload += value
+		}
+	}
+	return payload
+}
+
+// SignaturePayload returns the exact string signed for a request to path with data at timestamp,
+// which helps comparing it against the CryptoMKT spec when a signature is rejected
+func SignaturePayload(timestamp int64, path string, data map[string]string) string {
+	values := url.Values{}
+	for k, v := range data {
+		values.Add(k, v)
+	}
+	return buildSignaturePayload(timestamp, path, values)
+}
+
 func (c Client) formHeaders(req *http.Request, path string, data url.Values) {
 	req.Header.Add("X-MKT-APIKEY", c.key)
 
-	t := time.Now().Unix()
-	body := strconv.FormatInt(t, 10) + "/" + version + path
+	// CryptoMKT only accepts timestamps in seconds and has no nonce, so requests signed
+	// within the same second share their timestamp
+	t := c.now().Add(c.ClockSkew()).Unix()
 	if data != nil {
 		req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
 		req.Header.Add("Content-Length", strconv.Itoa(len(data.Encode())))
-		for _, values := range data {
-			for _, value := range values {
-				body += value
-			}
-		}
 	}
 
 	h := hmac.New(sha512.New384, []byte(c.secret))
-	h.Write([]byte(body))
+	h.Write([]byte(buildSignaturePayload(t, path, data)))
 
 	req.Header.Add("X-MKT-SIGNATURE", hex.EncodeToString(h.Sum(nil)))
 	req.Header.Add("X-MKT-TIMESTAMP", strconv.FormatInt(t, 10))
 }
 
-func (c Client) get(path string, params map[string]string, auth bool) (*http.Response, error) {
+// wait blocks until the rate limiter, if any, allows a new request
+func (c Client) wait(ctx context.Context) error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.Wait(ctx)
+}
+
+// send sets the headers shared by every request and makes it
+func (c Client) send(req *http.Request) (*http.Response, error) {
+	return c.sendRequest(req, false)
+}
+
+// sendRequest makes req like send. The concurrency slot and the WithRequestTimeout deadline of a
+// request normally last until its body is closed, but with stream they only cover getting its
+// headers, since a streamed body is read while the callbacks of the caller run.
+func (c Client) sendRequest(req *http.Request, stream bool) (*http.Response, error) {
+	id := setRequestID(req)
+	if c.compression {
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	release, err := c.acquire(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("request %s: %w", id, err)
+	}
+
+	var cancel context.CancelFunc = func() {}
+	var stop = func() bool { return false }
+	if c.requestTimeout > 0 {
+		var ctx context.Context
+		if stream {
+			// A timer instead of a deadline, so it can be stopped once the headers arrive
+			ctx, cancel = context.WithCancel(req.Context())
+			stop = time.AfterFunc(c.requestTimeout, cancel).Stop
+		} else {
+			ctx, cancel = context.WithTimeout(req.Context(), c.requestTimeout)
+		}
+		req = req.WithContext(ctx)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		cancel()
+		release()
+		return nil, fmt.Errorf("request %s: %w", id, err)
+	}
+	c.observeSkew(res)
+	if stream {
+		stop()
+		release()
+	}
+	res.Body = cancelOnClose{res.Body, func() {
+		cancel()
+		release()
+	}}
+	return res, nil
+}
+
+// acquire takes one of the slots set with WithMaxConcurrency, waiting until one frees up or ctx is
+// done, and returns the func that gives it back, which is safe to call more than once
+func (c Client) acquire(ctx context.Context) (func(), error) {
+	if c.inFlight == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case c.inFlight <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	var once sync.Once
+	return func() { once.Do(func() { <-c.inFlight }) }, nil
+}
+
+// cancelOnClose releases the context and the concurrency slot of a request once its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// responseBody returns the body of res, decompressing it if the Accept-Encoding
+// header was set explicitly and the transport didn't handle it
+func responseBody(res *http.Response) (io.ReadCloser, error) {
+	switch strings.ToLower(res.Header.Get("Content-Encoding")) {
+	case "gzip":
+		return gzip.NewReader(res.Body)
+	case "deflate":
+		return zlib.NewReader(res.Body)
+	}
+	return res.Body, nil
+}
+
+// get makes a GET request. GET endpoints only read data, so they are retried as set with WithRetry.
+func (c Client) get(ctx context.Context, path string, params map[string]string, auth bool) (*http.Response, error) {
+	return c.retry(ctx, func() (*http.Response, error) {
+		res, err := c.getOnce(ctx, path, params, auth, false)
+		if err != nil {
+			return nil, err
+		}
+		return c.bufferBody(res)
+	})
+}
+
+// getStream is like get, but it leaves the body unread so it can be decoded as it arrives, see
+// decodeEach. A read cut short by the connection fails its decoding instead of being retried, and
+// reading the body holds neither a concurrency slot nor the request timeout, so the callbacks run
+// meanwhile may take their time and make requests of their own.
+func (c Client) getStream(ctx context.Context, path string, params map[string]string, auth bool) (*http.Response, error) {
+	return c.retry(ctx, func() (*http.Response, error) {
+		return c.getOnce(ctx, path, params, auth, true)
+	})
+}
+
+// bufferBody reads the body of res up front, so a read cut short by the connection fails the
+// request itself, which retry repeats, instead of its decoding. Bodies over the size limit are
+// left for decode to reject.
+func (c Client) bufferBody(res *http.Response) (*http.Response, error) {
+	buf, err := ioutil.ReadAll(io.LimitReader(res.Body, c.responseLimit()+1))
+	if err != nil {
+		res.Body.Close()
+		return nil, fmt.Errorf("read response of request %s: %w", requestID(res), truncated(err))
+	}
+	res.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), res.Body), res.Body}
+	return res, nil
+}
+
+// truncated wraps err with ErrTruncatedResponse if it comes from a body that ended too early
+func truncated(err error) error {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %s", ErrTruncatedResponse, err)
+	}
+	return err
+}
+
+// responseLimit returns the size limit of response bodies set with WithMaxResponseSize
+func (c Client) responseLimit() int64 {
+	if c.maxResponseSize <= 0 {
+		return defaultMaxResponseSize
+	}
+	return c.maxResponseSize
+}
+
+// getOnce makes a GET request once, streaming its body if stream is set, see sendRequest
+func (c Client) getOnce(ctx context.Context, path string, params map[string]string, auth, stream bool) (*http.Response, error) {
 	var err error
 
+	if err = c.wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// First, create the request url with the params map
-	requestURL, err := c.formURL(apiURL+version+path, params)
+	requestURL, err := c.formURL(c.endpoint(path), params)
 	if err != nil {
 		return nil, err
 	}
 
 	// Then, create the http Client and set the headers if needed
-	req, err := http.NewRequest("GET", requestURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", requestURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("Request failed: %s", err)
 	}
@@ -124,14 +412,25 @@ func (c Client) get(path string, params map[string]string, auth bool) (*http.Res
 	}
 
 	// Make the request
-	return c.client.Do(req)
+	return c.sendRequest(req, stream)
 }
 
-func (c Client) post(path string, data map[string]string) (*http.Response, error) {
+// post makes a POST request. POST endpoints create or cancel orders and the API has no
+// idempotency keys to tell a repeated request apart, so they are never retried.
+func (c Client) post(ctx context.Context, path string, data map[string]string) (*http.Response, error) {
 	var err error
 
+	// Every request that changes the account is a POST
+	if c.readOnly {
+		return nil, ErrReadOnlyClient
+	}
+
+	if err = c.wait(ctx); err != nil {
+		return nil, err
+	}
+
 	// First, create the request url with the params map
-	requestURL, err := c.formURL(apiURL+version+path, nil)
+	requestURL, err := c.formURL(c.endpoint(path), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -142,223 +441,456 @@ func (c Client) post(path string, data map[string]string) (*http.Response, error
 	}
 
 	// Then, create the http Client and set the headers
-	req, err := http.NewRequest("POST", requestURL, strings.NewReader(payload.Encode()))
+	req, err := http.NewRequestWithContext(ctx, "POST", requestURL, strings.NewReader(payload.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("Request failed: %s", err)
 	}
 	c.formHeaders(req, path, payload)
 
 	// Make the request
-	return c.client.Do(req)
+	return c.send(req)
+}
+
+// pageLimit returns the number of items requested per page
+func (c Client) pageLimit() int {
+	if c.perPage == 0 {
+		return maxLimit
+	}
+	return c.perPage
+}
+
+// checkPage validates page and the page limit of the Client before requesting a paginated endpoint
+func (c Client) checkPage(page int) error {
+	if page < 0 {
+		return ErrInvalidPage
+	}
+	if l := c.pageLimit(); l < minLimit || l > maxLimit {
+		return fmt.Errorf("%w: %d, it must be between %d and %d", ErrLimitOutOfRange, l, minLimit, maxLimit)
+	}
+	return nil
+}
+
+// IsSuccess reports whether status is the one CryptoMKT uses for successful responses
+func IsSuccess(status string) bool {
+	return status == "success"
+}
+
+// decode reads the JSON body of res into result, or returns an *APIError if the API
+// reports that the request failed, or an *HTTPError if it failed with a non-2xx status and
+// no error of the API. Other errors are prefixed by the path of the endpoint, the HTTP status
+// code and the ID of the request.
+func (c Client) decode(res *http.Response, path string, result interface{}) error {
+	fail := func(op string, err error) error {
+		return fmt.Errorf("%s %s (%d, request %s): %w", op, path, res.StatusCode, requestID(res), err)
+	}
+
+	r, err := responseBody(res)
+	if err != nil {
+		return fail("read", err)
+	}
+	defer r.Close()
+
+	max := c.responseLimit()
+	body, err := ioutil.ReadAll(io.LimitReader(r, max+1))
+	if err != nil {
+		return fail("read", truncated(err))
+	}
+	if int64(len(body)) > max {
+		return fail("read", ErrResponseTooLarge)
+	}
+
+	var status struct {
+		Status  string
+		Message string
+	}
+	err = unmarshal(body, &status)
+	if err != nil || status.Status == "" {
+		if underMaintenance(res, body) {
+			return &MaintenanceError{RetryAfter: c.retryAfter(res)}
+		}
+		if res.StatusCode < 200 || res.StatusCode > 299 {
+			return &HTTPError{Code: res.StatusCode, Body: string(body), RequestID: requestID(res)}
+		}
+	}
+	if err != nil {
+		return fail("decode", truncated(err))
+	}
+	if !IsSuccess(status.Status) {
+		if underMaintenance(res, []byte(status.Message)) {
+			return &MaintenanceError{RetryAfter: c.retryAfter(res)}
+		}
+		return &APIError{Status: status.Status, Message: status.Message, StatusCode: res.StatusCode, RequestID: requestID(res)}
+	}
+
+	if result == nil {
+		return nil
+	}
+	if err = unmarshal(body, result); err != nil {
+		return fail("decode", truncated(err))
+	}
+	return nil
+}
+
+// underMaintenance reports whether res, with the given body, is the answer of the API
+// while it's down for maintenance
+func underMaintenance(res *http.Response, body []byte) bool {
+	if res.StatusCode == http.StatusServiceUnavailable {
+		return true
+	}
+	text := bytes.ToLower(body)
+	return bytes.Contains(text, []byte("maintenance")) || bytes.Contains(text, []byte("mantenimiento"))
+}
+
+// retryAfter returns the wait advised by the Retry-After header of res, or 0 if it has none
+func (c Client) retryAfter(res *http.Response) time.Duration {
+	header := res.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(header); err == nil {
+		return date.Sub(c.now())
+	}
+	return 0
+}
+
+// unmarshal decodes data into v keeping JSON numbers as json.Number when v has interface{}
+// values, so no number loses precision by going through a float64. Amounts and prices are
+// strings in every response type of the package and are never affected.
+func unmarshal(data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
 }
 
 // Markets returns a *MarketResponse with an array of Markets
-func (c Client) Markets() (*MarketResponse, error) {
+func (c Client) Markets(ctx context.Context) (*MarketResponse, error) {
 	path := "market"
 
-	res, err := c.get(path, nil, false)
+	res, err := c.get(ctx, path, nil, false)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result MarketResponse
-	err = json.NewDecoder(res.Body).Decode(&result)
+	err = c.decode(res, path, &result)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+		return nil, err
 	}
 	return &result, nil
 }
 
 // Ticker returns a *TickerResponse with the status of a Market
-func (c Client) Ticker(market Market) (*TickerResponse, error) {
+func (c Client) Ticker(ctx context.Context, market Market) (*TickerResponse, error) {
 	params := map[string]string{"market": string(market)}
 	path := "ticker"
 
-	res, err := c.get(path, params, false)
+	res, err := c.get(ctx, path, params, false)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result TickerResponse
-	err = json.NewDecoder(res.Body).Decode(&result)
+	err = c.decode(res, path, &result)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+		return nil, err
 	}
 	return &result, nil
 }
 
-// Book returns an *OrderBookResponse with an array of OrderBookOrders
-func (c Client) Book(market Market, ot OrderType, page int) (*OrderBookResponse, error) {
-	params := map[string]string{"market": string(market), "type": string(ot), "page": strconv.Itoa(page), "limit": strconv.Itoa(limit)}
+// TickerOne returns the *Ticker of a Market, without the response around it
+func (c Client) TickerOne(ctx context.Context, market Market) (*Ticker, error) {
+	res, err := c.Ticker(ctx, market)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker, ok := res.First()
+	if !ok {
+		return nil, fmt.Errorf("no ticker for %s", market)
+	}
+	return &ticker, nil
+}
+
+// Book returns an *OrderBookResponse with an array of OrderBookOrders.
+// Pages start at 0 in every paginated endpoint, a negative page returns ErrInvalidPage.
+func (c Client) Book(ctx context.Context, market Market, ot OrderType, page int) (*OrderBookResponse, error) {
+	if err := c.checkPage(page); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{"market": string(market), "type": string(ot), "page": strconv.Itoa(page), "limit": strconv.Itoa(c.pageLimit())}
 	path := "book"
 
-	res, err := c.get(path, params, false)
+	res, err := c.get(ctx, path, params, false)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result OrderBookResponse
-	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+	if err = c.decode(res, path, &result); err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
 
 // BuyBook returns an *OrderBookResponse with an array of BUY OrderBookOrders
-func (c Client) BuyBook(market Market, page int) (*OrderBookResponse, error) {
-	return c.Book(market, BUY, page)
+func (c Client) BuyBook(ctx context.Context, market Market, page int) (*OrderBookResponse, error) {
+	return c.Book(ctx, market, BUY, page)
 }
 
 // SellBook returns an *OrderBookResponse with an array of SELL OrderBookOrders
-func (c Client) SellBook(market Market, page int) (*OrderBookResponse, error) {
-	return c.Book(market, SELL, page)
+func (c Client) SellBook(ctx context.Context, market Market, page int) (*OrderBookResponse, error) {
+	return c.Book(ctx, market, SELL, page)
 }
 
-// Trades returns a *TradesResponse with an array of Trades
-func (c Client) Trades(market Market, start string, end string, page int) (*TradesResponse, error) {
-	params := map[string]string{"market": string(market), "start": start, "end": end, "page": strconv.Itoa(page), "limit": strconv.Itoa(limit)}
+// Trades returns a *TradesResponse with an array of Trades. start and end are dates in the
+// 2006-01-02 layout, or empty to leave them out, and fail with ErrInvalidDateFormat otherwise.
+func (c Client) Trades(ctx context.Context, market Market, start string, end string, page int) (*TradesResponse, error) {
+	if err := c.checkPage(page); err != nil {
+		return nil, err
+	}
+	for _, date := range []string{start, end} {
+		if err := checkTradesDate(date); err != nil {
+			return nil, err
+		}
+	}
+
+	params := map[string]string{"market": string(market), "start": start, "end": end, "page": strconv.Itoa(page), "limit": strconv.Itoa(c.pageLimit())}
 	path := "trades"
 
-	res, err := c.get(path, params, false)
+	res, err := c.get(ctx, path, params, false)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result TradesResponse
-	err = json.NewDecoder(res.Body).Decode(&result)
+	err = c.decode(res, path, &result)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+		return nil, err
+	}
+	return &result, nil
+}
+
+// checkTradesDate returns an error wrapping ErrInvalidDateFormat if date is set and isn't in tradesDateLayout
+func checkTradesDate(date string) error {
+	if date == "" {
+		return nil
+	}
+	if _, err := time.Parse(tradesDateLayout, date); err != nil {
+		return fmt.Errorf("%w %q, expected %s", ErrInvalidDateFormat, date, tradesDateLayout)
+	}
+	return nil
+}
+
+// Prices returns a *PricesResponse with the ask and bid Candles of a Market, newest first
+func (c Client) Prices(ctx context.Context, market Market, tf Timeframe, page int) (*PricesResponse, error) {
+	if !tf.Valid() {
+		return nil, ErrInvalidTimeframe
+	}
+	if err := c.checkPage(page); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{"market": string(market), "timeframe": string(tf), "page": strconv.Itoa(page), "limit": strconv.Itoa(c.pageLimit())}
+	path := "prices"
+
+	res, err := c.get(ctx, path, params, false)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var result PricesResponse
+	if err = c.decode(res, path, &result); err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
 
 // ActiveOrders returns an *OrdersResponse with an array of ActiveOrders
-func (c Client) ActiveOrders(market Market, page int) (*OrdersResponse, error) {
-	params := map[string]string{"market": string(market), "page": strconv.Itoa(page), "limit": strconv.Itoa(limit)}
+func (c Client) ActiveOrders(ctx context.Context, market Market, page int) (*OrdersResponse, error) {
+	if err := c.checkPage(page); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{"market": string(market), "page": strconv.Itoa(page), "limit": strconv.Itoa(c.pageLimit())}
 	path := "orders/active"
 
-	res, err := c.get(path, params, true)
+	res, err := c.get(ctx, path, params, true)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result OrdersResponse
-	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+	if err = c.decode(res, path, &result); err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
 
 // ExecutedOrders returns an *OrdersResponse with an array of ExecutedOrders
-func (c Client) ExecutedOrders(market Market, page int) (*OrdersResponse, error) {
-	params := map[string]string{"market": string(market), "page": strconv.Itoa(page), "limit": strconv.Itoa(limit)}
+func (c Client) ExecutedOrders(ctx context.Context, market Market, page int) (*OrdersResponse, error) {
+	if err := c.checkPage(page); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{"market": string(market), "page": strconv.Itoa(page), "limit": strconv.Itoa(c.pageLimit())}
 	path := "orders/executed"
 
-	res, err := c.get(path, params, true)
+	res, err := c.get(ctx, path, params, true)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result OrdersResponse
-	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+	if err = c.decode(res, path, &result); err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
 
-// CreateOrder creates an Order and returns an *OrderResponse with the created Order
-func (c Client) CreateOrder(market Market, amount float64, price float64, ot OrderType) (*OrderResponse, error) {
+// CreateOrder creates an Order and returns an *OrderResponse with the created Order.
+// Orders below the minimum set with WithMinNotional fail with a *MinNotionalError,
+// or have their amount raised to it if the option allows it.
+func (c Client) CreateOrder(ctx context.Context, market Market, amount float64, price float64, ot OrderType) (*OrderResponse, error) {
+	return c.PlaceOrder(ctx, CreateOrderRequest{Market: market, Amount: amount, Price: price, Type: ot})
+}
+
+// PlaceOrder creates the Order described by req, like CreateOrder, formatting its
+// amount and price with the precisions of req when they're set
+func (c Client) PlaceOrder(ctx context.Context, req CreateOrderRequest) (*OrderResponse, error) {
+	precision := amountPrecision(MarketAssetMapping[req.Market], req.AmountPrecision)
+	amount, err := c.checkMinNotional(req.Market, req.Amount, req.Price, precision)
+	if err != nil {
+		return nil, err
+	}
+
 	data := map[string]string{
-		"amount": strconv.FormatFloat(amount, 'f', 4, 64),
-		"market": string(market),
-		"price":  strconv.FormatFloat(price, 'f', 4, 64),
-		"type":   string(ot),
+		"amount": strconv.FormatFloat(amount, 'f', precision, 64),
+		"market": string(req.Market),
+		"price":  formatPrice(req.Price, req.PricePrecision),
+		"type":   string(req.Type),
 	}
 	path := "orders/create"
 
-	res, err := c.post(path, data)
+	res, err := c.post(ctx, path, data)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result OrderResponse
-	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+	if err = c.decode(res, path, &result); err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
 
-// OrderStatus returns an *OrderResponse with the status of an Order
-func (c Client) OrderStatus(ID string) (*OrderResponse, error) {
+// OrderStatus returns an *OrderResponse with the status of an Order, or ErrOrderNotFound
+// if the Order doesn't exist
+func (c Client) OrderStatus(ctx context.Context, ID string) (*OrderResponse, error) {
 	var params = map[string]string{"id": ID}
 	path := "orders/status"
 
-	res, err := c.get(path, params, true)
+	res, err := c.get(ctx, path, params, true)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
+	// Only the API says whether the Order is missing, a bare 404 of a proxy is an HTTPError
 	var result OrderResponse
-	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+	if err = c.decode(res, path, &result); err != nil {
+		if orderNotFound(err) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	if result.Data.ID == "" {
+		return nil, ErrOrderNotFound
 	}
 	return &result, nil
 }
 
-// CancelOrder cancels an Order and returns an *OrderResponse with the status of the Order
-func (c Client) CancelOrder(ID string) (*OrderResponse, error) {
+// CancelOrder cancels an Order and returns an *OrderResponse with the status of the Order, or
+// ErrOrderNotFound if the Order doesn't exist
+func (c Client) CancelOrder(ctx context.Context, ID string) (*OrderResponse, error) {
 	data := map[string]string{"id": ID}
 	path := "orders/cancel"
 
-	res, err := c.post(path, data)
+	res, err := c.post(ctx, path, data)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result OrderResponse
-	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+	if err = c.decode(res, path, &result); err != nil {
+		if orderNotFound(err) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Transactions returns a *TransactionsResponse with the movements of the Wallet of a currency
+func (c Client) Transactions(ctx context.Context, currency WalletType, page int) (*TransactionsResponse, error) {
+	if err := c.checkPage(page); err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{"currency": string(currency), "page": strconv.Itoa(page), "limit": strconv.Itoa(c.pageLimit())}
+	path := "transactions"
+
+	res, err := c.get(ctx, path, params, true)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var result TransactionsResponse
+	if err = c.decode(res, path, &result); err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
 
 // Balance returns a *BalanceResponse with the status of the Wallets
-func (c Client) Balance() (*BalanceResponse, error) {
+func (c Client) Balance(ctx context.Context) (*BalanceResponse, error) {
 	path := "balance"
 
-	res, err := c.get(path, nil, true)
+	res, err := c.get(ctx, path, nil, true)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result BalanceResponse
-	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("error decoding: %s", err)
+	if err = c.decode(res, path, &result); err != nil {
+		return nil, err
 	}
 	return &result, nil
 }
+
 // InstantGet Allows you to Find out how much you would receive/need if you were to sell/buy at market price your crypto.
-func (c Client) InstantGet(market Market, ot OrderType, amount string) (*InstantGetResponse, error) {
+func (c Client) InstantGet(ctx context.Context, market Market, ot OrderType, amount string) (*InstantGetResponse, error) {
 	params := map[string]string{"market": string(market), "type": string(ot), "amount": amount}
 	path := "orders/instant/get"
-	res, err := c.get(path, params, true)
+	res, err := c.get(ctx, path, params, true)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result InstantGetResponse
-	err = json.NewDecoder(res.Body).Decode(&result)
+	err = c.decode(res, path, &result)
 	if err != nil {
 		return nil, err
 	}
@@ -367,20 +899,55 @@ func (c Client) InstantGet(market Market, ot OrderType, amount string) (*Instant
 }
 
 // InstantCreate Allows you to create an order that will be executed at market price.
-func (c Client) InstantCreate(market Market, ot OrderType, amount string) (*InstantCreateResponse, error) {
+// Instant orders are executed at market price as soon as they are created, so by the time their
+// ID is known there is nothing left to cancel, and CancelOrder is only meant for the orders
+// created with CreateOrder.
+func (c Client) InstantCreate(ctx context.Context, market Market, ot OrderType, amount string) (*InstantCreateResponse, error) {
 	params := map[string]string{"market": string(market), "type": string(ot), "amount": amount}
 	path := "orders/instant/create"
-	res, err := c.post(path, params)
+	res, err := c.post(ctx, path, params)
 	if err != nil {
 		return nil, err
 	}
 	defer res.Body.Close()
 
 	var result InstantCreateResponse
-	err = json.NewDecoder(res.Body).Decode(&result)
+	err = c.decode(res, path, &result)
 	if err != nil {
 		return nil, err
 	}
 
 	return &result, nil
 }
+
+// Do calls an endpoint that the Client doesn't wrap yet and decodes its response into out,
+// with the same signing and error handling as the rest of the methods. path is relative to
+// the API version, like "orders/active", and method is either GET or POST. POST requests are
+// always signed, GET requests only when auth is set. out may be nil when only the outcome of the
+// request matters, then the response is checked for errors but not decoded.
+func (c Client) Do(ctx context.Context, method, path string, params map[string]string, auth bool, out interface{}) error {
+	_, err := c.DoWithResponse(ctx, method, path, params, auth, out)
+	return err
+}
+
+// DoWithResponse is like Do, but it also returns the *http.Response, so its headers and status
+// can be inspected. Its Body is already read and closed. The response is returned along with
+// the error when the API answered but decoding it failed.
+func (c Client) DoWithResponse(ctx context.Context, method, path string, params map[string]string, auth bool, out interface{}) (*http.Response, error) {
+	var res *http.Response
+	var err error
+	switch method {
+	case http.MethodGet:
+		res, err = c.get(ctx, path, params, auth)
+	case http.MethodPost:
+		res, err = c.post(ctx, path, params)
+	default:
+		return nil, fmt.Errorf("unsupported method %s", method)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return res, c.decode(res, path, out)
+}
@@ -0,0 +1,136 @@
+package cryptomkt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		wallet WalletType
+		amount float64
+		want   string
+	}{
+		{BTC, 0.123456789, "0.12345679"},
+		{BTC, 1, "1.00000000"},
+		{ETH, 2.5, "2.50000000"},
+		{XLM, 10.12345678, "10.1234568"},
+		{EOS, 3.14159, "3.1416"},
+		{ARS, 1234.567, "1234.57"},
+		{EUR, 0.1, "0.10"},
+		{CLP, 1500000.4, "1500000"},
+		{CLP, 999.5, "1000"},
+		{WalletType("XYZ"), 1.23456, "1.2346"},
+	}
+	for _, tt := range tests {
+		if got := FormatAmount(tt.wallet, tt.amount); got != tt.want {
+			t.Errorf("FormatAmount(%s, %v) = %q, want %q", tt.wallet, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestFormatPrecision(t *testing.T) {
+	two, zero := 2, 0
+	tests := []struct {
+		wallet    WalletType
+		amount    float64
+		precision *int
+		want      string
+	}{
+		{BTC, 0.123456789, nil, "0.12345679"},
+		{CLP, 1500.4, nil, "1500"},
+		{BTC, 0.123456789, &two, "0.12"},
+		{ARS, 1234.567, &zero, "1235"},
+	}
+	for _, tt := range tests {
+		if got := formatPrecision(tt.wallet, tt.amount, tt.precision); got != tt.want {
+			t.Errorf("formatPrecision(%s, %v) = %q, want %q", tt.wallet, tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceOrderFormatting(t *testing.T) {
+	three := 3
+	tests := []struct {
+		req           CreateOrderRequest
+		amount, price string
+	}{
+		{CreateOrderRequest{Market: XLMEUR, Amount: 100, Price: 0.0876}, "100.0000000", "0.0876"},
+		{CreateOrderRequest{Market: XLMCLP, Amount: 12.5, Price: 45.37}, "12.5000000", "45.3700"},
+		{CreateOrderRequest{Market: BTCCLP, Amount: 0.123456789, Price: 5000000}, "0.12345679", "5000000.0000"},
+		{CreateOrderRequest{Market: ETHEUR, Amount: 1.23456, Price: 150.12345, AmountPrecision: &three, PricePrecision: &three}, "1.235", "150.123"},
+	}
+	for _, tt := range tests {
+		var amount, price string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			amount, price = r.FormValue("amount"), r.FormValue("price")
+			fmt.Fprint(w, `{"status":"success","data":{"id":"M1"}}`)
+		}))
+		c := NewClientWithOptions("key", "secret", WithBaseURL(srv.URL+"/"))
+		_, err := c.PlaceOrder(context.Background(), tt.req)
+		srv.Close()
+		if err != nil {
+			t.Fatalf("PlaceOrder(%s): %v", tt.req.Market, err)
+		}
+		if amount != tt.amount || price != tt.price {
+			t.Errorf("PlaceOrder(%s) sent amount %q and price %q, want %q and %q", tt.req.Market, amount, price, tt.amount, tt.price)
+		}
+	}
+}
+
+func TestPlaceOrderAdjustsMinNotionalAtAmountPrecision(t *testing.T) {
+	var amount string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		amount = r.FormValue("amount")
+		fmt.Fprint(w, `{"status":"success","data":{"id":"M1"}}`)
+	}))
+	defer srv.Close()
+
+	one := 1
+	c := NewClientWithOptions("key", "secret", WithBaseURL(srv.URL+"/"), WithMinNotional(map[Market]float64{ETHEUR: 10}, true))
+	if _, err := c.PlaceOrder(context.Background(), CreateOrderRequest{Market: ETHEUR, Amount: 1, Price: 3, AmountPrecision: &one}); err != nil {
+		t.Fatalf("PlaceOrder: %v", err)
+	}
+	// 10/3 rounded up at 1 decimal, rounding it at the 8 decimals of ETH would send 3.3
+	if amount != "3.4" {
+		t.Errorf("PlaceOrder sent amount %q, want %q", amount, "3.4")
+	}
+}
+
+func TestBuildSignaturePayload(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		data url.Values
+		want string
+	}{
+		{"no body", "balance", nil, "1500000000/v1/balance"},
+		{"one value", "orders/cancel", url.Values{"id": {"M107441"}}, "1500000000/v1/orders/cancelM107441"},
+		{
+			"values sorted by key",
+			"orders/create",
+			url.Values{"type": {"buy"}, "price": {"1000"}, "market": {"ETHCLP"}, "amount": {"0.3"}},
+			"1500000000/v1/orders/create0.3ETHCLP1000buy",
+		},
+	}
+	for _, tt := range tests {
+		if got := buildSignaturePayload(1500000000, tt.path, tt.data); got != tt.want {
+			t.Errorf("%s: buildSignaturePayload = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSignaturePayloadMatchesRequests(t *testing.T) {
+	data := map[string]string{"market": "ETHCLP", "type": "sell", "amount": "1", "price": "2"}
+	values := url.Values{}
+	for k, v := range data {
+		values.Add(k, v)
+	}
+	if got, want := SignaturePayload(1, "orders/create", data), buildSignaturePayload(1, "orders/create", values); got != want {
+		t.Errorf("SignaturePayload = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,116 @@
+package cryptomkt
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters blocks until the Client is waiting on clock, which happens on another goroutine
+func waitForWaiters(t *testing.T, clock *FakeClock) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for clock.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the Client never waited on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// advanceThrough checks that the wait of the Client on clock lasts exactly d and lets it go
+func advanceThrough(t *testing.T, clock *FakeClock, d time.Duration) {
+	t.Helper()
+	clock.Advance(d - time.Millisecond)
+	if clock.Waiters() != 1 {
+		t.Fatalf("the wait ended before %s", d)
+	}
+	clock.Advance(time.Millisecond)
+}
+
+func TestRetryBackoffDoubles(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) <= 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, `{"status":"success","data":["ETHCLP"]}`)
+	}))
+	defer srv.Close()
+
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := NewClientWithOptions("key", "secret", WithBaseURL(srv.URL+"/"), WithClock(clock), WithRetry(4, time.Second))
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Markets(context.Background())
+		done <- err
+	}()
+
+	for i, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		waitForWaiters(t, clock)
+		if got := atomic.LoadInt32(&hits); got != int32(i+1) {
+			t.Fatalf("%d requests before backoff %s, want %d", got, backoff, i+1)
+		}
+		advanceThrough(t, clock, backoff)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("Markets: %v", err)
+	}
+	if got := atomic.LoadInt32(&hits); got != 4 {
+		t.Errorf("%d requests, want 4", got)
+	}
+}
+
+func TestPageDelay(t *testing.T) {
+	const pages, limit = 3, 20
+	trade := `{"market":"ETHCLP","market_taker":"buy","timestamp":"2020-01-01T00:00:00.000000","price":"1","amount":"1"}`
+
+	var requested int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requested, 1)
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		n, next := limit, strconv.Itoa(page+1)
+		if page == pages-1 {
+			n, next = 1, "null"
+		}
+		data := strings.TrimSuffix(strings.Repeat(trade+",", n), ",")
+		fmt.Fprintf(w, `{"status":"success","pagination":{"page":%d,"next":%s,"limit":%d},"data":[%s]}`, page, next, limit, data)
+	}))
+	defer srv.Close()
+
+	clock := NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := NewClientWithOptions("key", "secret", WithBaseURL(srv.URL+"/"), WithClock(clock), WithPageLimit(limit), WithPageDelay(5*time.Second))
+
+	var trades int32
+	done := make(chan error, 1)
+	go func() {
+		done <- c.EachTrade(context.Background(), ETHCLP, "2020-01-01", "2020-01-02", func(Trade) error {
+			atomic.AddInt32(&trades, 1)
+			return nil
+		})
+	}()
+
+	for i := 1; i < pages; i++ {
+		waitForWaiters(t, clock)
+		if got := atomic.LoadInt32(&requested); got != int32(i) {
+			t.Fatalf("%d pages requested before delay %d, want %d", got, i, i)
+		}
+		advanceThrough(t, clock, 5*time.Second)
+	}
+
+	if err := <-done; err != nil {
+		t.Fatalf("EachTrade: %v", err)
+	}
+	if got, want := atomic.LoadInt32(&trades), int32((pages-1)*limit+1); got != want {
+		t.Errorf("%d trades, want %d", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+package cryptomkt
+
+import "time"
+
+// ClientConfig is the configuration of a Client without its credentials, so it can be logged or
+// persisted and a Client rebuilt from it with NewFromConfig. The rate limiter, transport and clock
+// aren't data and can't be captured, they have to be passed again as options.
+type ClientConfig struct {
+	BaseURL           string             `json:"base_url,omitempty"`
+	UserAgent         string             `json:"user_agent,omitempty"`
+	Timeout           time.Duration      `json:"timeout"`
+	ValidateOnStart   bool               `json:"validate_on_start,omitempty"`
+	Compression       bool               `json:"compression,omitempty"`
+	MaxResponseSize   int64              `json:"max_response_size,omitempty"`
+	RequestTimeout    time.Duration      `json:"request_timeout,omitempty"`
+	RetryAttempts     int                `json:"retry_attempts,omitempty"`
+	RetryBackoff      time.Duration      `json:"retry_backoff,omitempty"`
+	PageLimit         int                `json:"page_limit,omitempty"`
+	PageDelay         time.Duration      `json:"page_delay,omitempty"`
+	ReadOnly          bool               `json:"read_only,omitempty"`
+	MaxConcurrency    int                `json:"max_concurrency,omitempty"`
+	MinNotional       map[Market]float64 `json:"min_notional,omitempty"`
+	AdjustMinNotional bool               `json:"adjust_min_notional,omitempty"`
+	SkewCorrection    bool               `json:"skew_correction,omitempty"`
+}
+
+// Config returns the configuration of the Client
+func (c Client) Config() ClientConfig {
+	cfg := ClientConfig{
+		BaseURL:           c.baseURL,
+		UserAgent:         c.userAgent,
+		ValidateOnStart:   c.validateOnStart,
+		Compression:       c.compression,
+		MaxResponseSize:   c.maxResponseSize,
+		RequestTimeout:    c.requestTimeout,
+		RetryAttempts:     c.retryAttempts,
+		RetryBackoff:      c.retryBackoff,
+		PageLimit:         c.perPage,
+		PageDelay:         c.pageDelay,
+		ReadOnly:          c.readOnly,
+		MaxConcurrency:    cap(c.inFlight),
+		AdjustMinNotional: c.adjustMinNotional,
+		SkewCorrection:    c.skew != nil,
+	}
+	if c.client != nil {
+		cfg.Timeout = c.client.Timeout
+	}
+	if c.minNotional != nil {
+		cfg.MinNotional = make(map[Market]float64, len(c.minNotional))
+		for m, min := range c.minNotional {
+			cfg.MinNotional[m] = min
+		}
+	}
+	return cfg
+}
+
+// NewFromConfig returns a Client for the given credentials configured like config, with opts
+// applied on top of it. Like New, it validates the credentials if config.ValidateOnStart is set.
+func NewFromConfig(config ClientConfig, key, secret string, opts ...Option) (*Client, error) {
+	base := []Option{
+		WithUserAgent(config.UserAgent),
+		WithMaxResponseSize(config.MaxResponseSize),
+		WithRequestTimeout(config.RequestTimeout),
+		WithRetry(config.RetryAttempts, config.RetryBackoff),
+		WithPageLimit(config.PageLimit),
+		WithPageDelay(config.PageDelay),
+		WithMaxConcurrency(config.MaxConcurrency),
+		WithMinNotional(config.MinNotional, config.AdjustMinNotional),
+	}
+	if config.BaseURL != "" {
+		base = append(base, WithBaseURL(config.BaseURL))
+	}
+	if config.ValidateOnStart {
+		base = append(base, WithValidateOnStart())
+	}
+	if config.Compression {
+		base = append(base, WithCompression())
+	}
+	if config.ReadOnly {
+		base = append(base, WithReadOnly())
+	}
+	if config.SkewCorrection {
+		base = append(base, WithClockSkewCorrection())
+	}
+	return New(key, secret, config.Timeout, append(base, opts...)...)
+}
@@ -0,0 +1,214 @@
+package cryptomkt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrOrderNotFound is returned when an Order doesn't exist or belongs to another account
+var ErrOrderNotFound = errors.New("order not found")
+
+// ErrInvalidPage is returned when a negative page is requested, pages start at 0
+var ErrInvalidPage = errors.New("invalid page, pages start at 0")
+
+// ErrResponseTooLarge is returned when a response body exceeds the size set with WithMaxResponseSize
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ErrInvalidTimeframe is returned when a Timeframe isn't one of the values accepted by the API
+var ErrInvalidTimeframe = errors.New("invalid timeframe")
+
+// ErrEmptyBook is returned when a book calculation needs orders on a side that has none
+var ErrEmptyBook = errors.New("empty book")
+
+// ErrStaleTicker is reported by PollTicker when the Ticker stops being updated
+var ErrStaleTicker = errors.New("stale ticker")
+
+// ErrMaintenance is wrapped by the MaintenanceError returned while CryptoMKT is under maintenance
+var ErrMaintenance = errors.New("under maintenance")
+
+// ErrBelowMinNotional is wrapped by the MinNotionalError of orders worth less than the minimum of their market
+var ErrBelowMinNotional = errors.New("below minimum notional")
+
+// ErrLimitOutOfRange is returned by paginated endpoints when the page limit isn't between 20 and 100
+var ErrLimitOutOfRange = errors.New("page limit out of range")
+
+// ErrReadOnlyClient is returned by the requests that change the account when the Client is read only
+var ErrReadOnlyClient = errors.New("read only client")
+
+// ErrWalletMismatch is returned when the Wallets of an order don't belong to its Market
+var ErrWalletMismatch = errors.New("wallets don't match market")
+
+// ErrInvalidDateFormat is wrapped by the error of Trades when start or end isn't a date like 2006-01-02
+var ErrInvalidDateFormat = errors.New("invalid date format")
+
+// ErrTruncatedResponse is wrapped by the error of a response cut short, when reading or decoding it ends in io.ErrUnexpectedEOF. Unlike malformed JSON, repeating the request may succeed.
+var ErrTruncatedResponse = errors.New("truncated response")
+
+// ErrInvalidAddress is wrapped by the error of ValidateAddress, along with the reason
+var ErrInvalidAddress = errors.New("invalid address")
+
+// ErrInsufficientFunds is wrapped by the APIError of orders that the balance can't cover
+var ErrInsufficientFunds = errors.New("insufficient funds")
+
+// ErrInvalidMarket is wrapped by the APIError of requests for a market that doesn't exist
+var ErrInvalidMarket = errors.New("invalid market")
+
+// ErrRateLimited is wrapped by the APIError of requests rejected for exceeding the rate limit
+var ErrRateLimited = errors.New("rate limited")
+
+// apiErrors maps fragments of the error messages of the API to the errors they stand for
+var apiErrors = []struct {
+	fragment string
+	err      error
+}{
+	{"not_enough_balance", ErrInsufficientFunds},
+	{"insufficient", ErrInsufficientFunds},
+	{"invalid_market", ErrInvalidMarket},
+	{"invalid market", ErrInvalidMarket},
+	{"rate limit", ErrRateLimited},
+	{"too many requests", ErrRateLimited},
+}
+
+// APIError is returned when CryptoMKT answers a request with a status other than "success".
+// Known messages unwrap to errors like ErrInsufficientFunds, so they can be checked with errors.Is.
+type APIError struct {
+	Status  string
+	Message string
+	// StatusCode is the HTTP status of the response
+	StatusCode int
+	RequestID  string
+}
+
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("api error: %s (%d, request %s)", e.Status, e.StatusCode, e.RequestID)
+	}
+	return fmt.Sprintf("api error: %s: %s (%d, request %s)", e.Status, e.Message, e.StatusCode, e.RequestID)
+}
+
+// Unwrap returns the error that the message of e stands for, or nil if it isn't a known one.
+// Responses with the 429 status unwrap to ErrRateLimited whatever their message.
+func (e *APIError) Unwrap() error {
+	msg := strings.ToLower(e.Message)
+	for _, known := range apiErrors {
+		if strings.Contains(msg, known.fragment) {
+			return known.err
+		}
+	}
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// orderNotFound reports whether err is an APIError saying that something wasn't found. The API
+// words it the same way on every endpoint, so it only stands for ErrOrderNotFound on the ones that
+// look up an Order, like OrderStatus and CancelOrder.
+func orderNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	msg := strings.ToLower(apiErr.Message)
+	return strings.Contains(msg, "not_found") || strings.Contains(msg, "not found")
+}
+
+// HTTPError is returned when the API answers with a non-2xx status and a body that isn't one of
+// its errors, like the pages of a proxy or a bare 401, so they can be told apart from malformed JSON.
+// Responses with the 429 status unwrap to ErrRateLimited.
+type HTTPError struct {
+	Code      int
+	Body      string
+	RequestID string
+}
+
+// httpErrorBodyLen bounds the part of the body of an HTTPError included in its message
+const httpErrorBodyLen = 200
+
+func (e *HTTPError) Error() string {
+	body := strings.TrimSpace(e.Body)
+	if len(body) > httpErrorBodyLen {
+		body = body[:httpErrorBodyLen] + "..."
+	}
+	return fmt.Sprintf("http error: %d %s: %q (request %s)", e.Code, http.StatusText(e.Code), body, e.RequestID)
+}
+
+// Unwrap returns ErrRateLimited for the 429 status, and nil otherwise
+func (e *HTTPError) Unwrap() error {
+	if e.Code == http.StatusTooManyRequests {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// MaintenanceError is returned when the API is down for maintenance
+type MaintenanceError struct {
+	// RetryAfter is the wait advised by the API before trying again, 0 if unknown
+	RetryAfter time.Duration
+}
+
+func (e *MaintenanceError) Error() string {
+	if e.RetryAfter <= 0 {
+		return ErrMaintenance.Error()
+	}
+	return fmt.Sprintf("%s, retry after %s", ErrMaintenance, e.RetryAfter)
+}
+
+// Unwrap returns ErrMaintenance
+func (e *MaintenanceError) Unwrap() error {
+	return ErrMaintenance
+}
+
+// MinNotionalError is returned by CreateOrder for orders worth less than the minimum set with WithMinNotional
+type MinNotionalError struct {
+	Market   Market
+	Min      float64
+	Notional float64
+}
+
+func (e *MinNotionalError) Error() string {
+	return fmt.Sprintf("%s: %s order is worth %g, the minimum is %g", ErrBelowMinNotional, e.Market, e.Notional, e.Min)
+}
+
+// Unwrap returns ErrBelowMinNotional
+func (e *MinNotionalError) Unwrap() error {
+	return ErrBelowMinNotional
+}
+
+// OrderErrors holds the errors of an operation over several Orders, indexed by Order ID
+type OrderErrors map[string]error
+
+func (e OrderErrors) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("%s: %s", id, e[id])
+	}
+	return fmt.Sprintf("%d orders failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// MarketErrors holds the errors of an operation over several Markets, indexed by Market
+type MarketErrors map[Market]error
+
+func (e MarketErrors) Error() string {
+	markets := make([]Market, 0, len(e))
+	for m := range e {
+		markets = append(markets, m)
+	}
+	sort.Slice(markets, func(i, j int) bool { return markets[i] < markets[j] })
+
+	msgs := make([]string, len(markets))
+	for i, m := range markets {
+		msgs[i] = fmt.Sprintf("%s: %s", m, e[m])
+	}
+	return fmt.Sprintf("%d markets failed: %s", len(e), strings.Join(msgs, "; "))
+}
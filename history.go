@@ -0,0 +1,70 @@
+package cryptomkt
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// TickerHistory returns a series of Tickers of market between from and to, built from the
+// candles of the Prices endpoint since CryptoMKT doesn't keep past tickers. Each Ticker is
+// timestamped at the start of its candle, with the close of the ask and bid candles as Ask
+// and Bid, and the high, low and volume of the bid candle. Tickers are sorted oldest first.
+func (c Client) TickerHistory(ctx context.Context, market Market, tf Timeframe, from, to time.Time) ([]Ticker, error) {
+	bids := map[time.Time]Candle{}
+	asks := map[time.Time]Candle{}
+	p := c.newPager(0, false)
+	for !p.done {
+		if err := p.pace(ctx); err != nil {
+			return nil, err
+		}
+
+		prices, err := c.Prices(ctx, market, tf, p.page)
+		if err != nil {
+			return nil, err
+		}
+
+		reachedFrom := false
+		for _, candle := range prices.Data.Bid {
+			if candle.CandleDate.Before(from) {
+				reachedFrom = true
+				continue
+			}
+			if !candle.CandleDate.After(to) {
+				bids[candle.CandleDate.Time] = candle
+			}
+		}
+		for _, candle := range prices.Data.Ask {
+			if !candle.CandleDate.Before(from) && !candle.CandleDate.After(to) {
+				asks[candle.CandleDate.Time] = candle
+			}
+		}
+
+		n := len(prices.Data.Bid)
+		if len(prices.Data.Ask) > n {
+			n = len(prices.Data.Ask)
+		}
+		if reachedFrom {
+			break
+		}
+		p.advance(prices.Pagination, n)
+	}
+
+	tickers := make([]Ticker, 0, len(bids))
+	for date, bid := range bids {
+		tickers = append(tickers, Ticker{
+			High:      bid.HighPrice,
+			Volume:    bid.VolumeSum,
+			Low:       bid.LowPrice,
+			Ask:       asks[date].ClosePrice,
+			Timestamp: bid.CandleDate,
+			Bid:       bid.ClosePrice,
+			LastPrice: bid.ClosePrice,
+			Market:    market,
+		})
+	}
+	sort.Slice(tickers, func(i, j int) bool {
+		return tickers[i].Timestamp.Before(tickers[j].Timestamp.Time)
+	})
+	return tickers, nil
+}
@@ -0,0 +1,75 @@
+package cryptomkt
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// instantPollInterval is the time between status checks of an instant order
+const instantPollInterval = time.Second
+
+// InstantCreateAndConfirm creates an instant order and waits until it is settled.
+// The quote is requested right before the order is created, so it's only an estimate
+// of the obtained/required amounts, the settled ones are in Order when it's known. If
+// the API returns an order ID that can be followed with OrderStatus, it is polled until
+// the order is no longer active, otherwise the successful creation is taken as the
+// confirmation. Errors after the creation are returned along with the result holding
+// the OrderID, since the order was already placed and must not be created again.
+func (c Client) InstantCreateAndConfirm(ctx context.Context, market Market, ot OrderType, amount string) (*InstantResult, error) {
+	quote, err := c.InstantGet(ctx, market, ot, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := c.InstantCreate(ctx, market, ot, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &InstantResult{Quote: quote.Data, OrderID: created.OrderID()}
+	for {
+		status, err := c.OrderStatus(ctx, result.OrderID)
+		if errors.Is(err, ErrOrderNotFound) {
+			// The order can't be tracked, there's nothing more to wait for
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+
+		if status.Data.Status != "active" {
+			result.Order = &status.Data
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-c.after(instantPollInterval):
+		}
+	}
+}
+
+// InstantEffectivePrice returns the all-in price of an instant order, in units of the currency of
+// market per unit of its asset for both BUY and SELL, so it compares directly with the prices of
+// the book. For a BUY it's Required/Obtained, what's paid per unit bought, and for a SELL it's
+// Obtained/Required, what's received per unit sold.
+func (c Client) InstantEffectivePrice(ctx context.Context, market Market, ot OrderType, amount string) (float64, error) {
+	res, err := c.InstantGet(ctx, market, ot, amount)
+	if err != nil {
+		return 0, err
+	}
+
+	rate, err := res.Data.Rate()
+	if err != nil {
+		return 0, err
+	}
+	if ot == BUY {
+		return rate, nil
+	}
+	if rate == 0 {
+		return 0, errors.New("quote requires nothing")
+	}
+	return 1 / rate, nil
+}
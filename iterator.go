@@ -0,0 +1,153 @@
+package cryptomkt
+
+import (
+	"context"
+	"time"
+)
+
+// pager follows the Pagination of an endpoint, forwards through Next or backwards through Previous
+type pager struct {
+	page    int
+	limit   int
+	delay   time.Duration
+	sleep   func(context.Context, time.Duration) error
+	reverse bool
+	started bool
+	done    bool
+	err     error
+}
+
+// newPager returns a pager starting at page with the page limit and delay of the Client
+func (c Client) newPager(page int, reverse bool) pager {
+	return pager{page: page, limit: c.pageLimit(), delay: c.pageDelay, sleep: c.sleep, reverse: reverse}
+}
+
+// pace waits the delay set with WithPageDelay before fetching every page but the first one,
+// unless ctx is done first
+func (p *pager) pace(ctx context.Context) error {
+	if p.started && p.delay > 0 {
+		if err := p.sleep(ctx, p.delay); err != nil {
+			return err
+		}
+	}
+	p.started = true
+	return nil
+}
+
+// advance moves the pager past a page with n items and the given Pagination. Going backwards
+// it stops after page 0, where Previous comes as "null", and going forwards once Next does.
+func (p *pager) advance(pagination Pagination, n int) {
+	if p.reverse {
+		if p.page == 0 || int(pagination.Previous) >= p.page {
+			p.done = true
+			return
+		}
+		p.page = int(pagination.Previous)
+		return
+	}
+
+	if n < p.limit || int(pagination.Next) <= p.page {
+		p.done = true
+		return
+	}
+	p.page = int(pagination.Next)
+}
+
+// Err returns the error that stopped the iteration, if any
+func (p *pager) Err() error {
+	return p.err
+}
+
+// TradesIterator walks the pages of the Trades endpoint
+type TradesIterator struct {
+	pager
+	ctx        context.Context
+	c          Client
+	market     Market
+	start, end string
+	trades     []Trade
+}
+
+// IterateTrades returns a *TradesIterator over the Trades of market between start and end,
+// beginning at page. If reverse is set it moves towards page 0 instead of away from it.
+// Every page is fetched with ctx.
+func (c Client) IterateTrades(ctx context.Context, market Market, start, end string, page int, reverse bool) *TradesIterator {
+	return &TradesIterator{
+		pager:  c.newPager(page, reverse),
+		ctx:    ctx,
+		c:      c,
+		market: market,
+		start:  start,
+		end:    end,
+	}
+}
+
+// Next fetches the next page, and returns false when there are no more pages or a request failed
+func (it *TradesIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if it.err = it.pace(it.ctx); it.err != nil {
+		return false
+	}
+
+	res, err := it.c.Trades(it.ctx, it.market, it.start, it.end, it.page)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.trades = res.Data
+	it.advance(res.Pagination, len(res.Data))
+	return true
+}
+
+// Trades returns the Trades of the page fetched by the last call to Next
+func (it *TradesIterator) Trades() []Trade {
+	return it.trades
+}
+
+// BookIterator walks the pages of one side of the Book endpoint
+type BookIterator struct {
+	pager
+	ctx    context.Context
+	c      Client
+	market Market
+	ot     OrderType
+	orders []OrderBookOrder
+}
+
+// IterateBook returns a *BookIterator over the ot side of the book of market, beginning at page.
+// If reverse is set it moves towards page 0 instead of away from it. Every page is fetched with ctx.
+func (c Client) IterateBook(ctx context.Context, market Market, ot OrderType, page int, reverse bool) *BookIterator {
+	return &BookIterator{
+		pager:  c.newPager(page, reverse),
+		ctx:    ctx,
+		c:      c,
+		market: market,
+		ot:     ot,
+	}
+}
+
+// Next fetches the next page, and returns false when there are no more pages or a request failed
+func (it *BookIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	if it.err = it.pace(it.ctx); it.err != nil {
+		return false
+	}
+
+	res, err := it.c.Book(it.ctx, it.market, it.ot, it.page)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	it.orders = res.Data
+	it.advance(res.Pagination, len(res.Data))
+	return true
+}
+
+// Orders returns the OrderBookOrders of the page fetched by the last call to Next
+func (it *BookIterator) Orders() []OrderBookOrder {
+	return it.orders
+}
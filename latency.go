@@ -0,0 +1,41 @@
+package cryptomkt
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// LatencyStats summarizes the round trips measured by MeasureLatency
+type LatencyStats struct {
+	Samples int
+	Min     time.Duration
+	Median  time.Duration
+	Max     time.Duration
+}
+
+// MeasureLatency times samples calls to the Markets endpoint, the lightest one of the API, and
+// returns their round trips. Each one includes waiting on the rate limiter, if one is set, and the
+// one-way delay can be taken as roughly half of them.
+func (c Client) MeasureLatency(ctx context.Context, samples int) (LatencyStats, error) {
+	if samples <= 0 {
+		return LatencyStats{}, errors.New("samples must be positive")
+	}
+
+	rtts := make([]time.Duration, samples)
+	for i := range rtts {
+		start := c.now()
+		if _, err := c.Markets(ctx); err != nil {
+			return LatencyStats{}, err
+		}
+		rtts[i] = c.now().Sub(start)
+	}
+	sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+
+	median := rtts[samples/2]
+	if samples%2 == 0 {
+		median = (rtts[samples/2-1] + rtts[samples/2]) / 2
+	}
+	return LatencyStats{Samples: samples, Min: rtts[0], Median: median, Max: rtts[samples-1]}, nil
+}
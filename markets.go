@@ -0,0 +1,76 @@
+package cryptomkt
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// marketStaleAfter is how old a Ticker can be for its market to be considered active
+const marketStaleAfter = 15 * time.Minute
+
+// IsMarketActive reports whether market looks tradeable. The API has no market status, so it's
+// inferred: the market has to be listed by Markets, its Ticker updated within the last 15 minutes,
+// and both sides of its book must have orders.
+func (c Client) IsMarketActive(ctx context.Context, market Market) (bool, error) {
+	markets, err := c.Markets(ctx)
+	if err != nil {
+		return false, err
+	}
+	listed := false
+	for _, m := range markets.Data {
+		if m == market {
+			listed = true
+			break
+		}
+	}
+	if !listed {
+		return false, nil
+	}
+
+	ticker, err := c.TickerOne(ctx, market)
+	if err != nil {
+		return false, err
+	}
+	if ticker.Age(c.now()) > marketStaleAfter {
+		return false, nil
+	}
+
+	for _, side := range []OrderType{BUY, SELL} {
+		book, err := c.Book(ctx, market, side, 0)
+		if err != nil {
+			return false, err
+		}
+		if len(book.Data) == 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// SortedMarkets returns the known Markets, those of MarketAssetMapping, in alphabetical order
+func SortedMarkets() []Market {
+	markets := make([]Market, 0, len(MarketAssetMapping))
+	for m := range MarketAssetMapping {
+		markets = append(markets, m)
+	}
+	sort.Slice(markets, func(i, j int) bool { return markets[i] < markets[j] })
+	return markets
+}
+
+// SortedWallets returns the known WalletTypes, the assets and currencies of the known Markets,
+// in alphabetical order
+func SortedWallets() []WalletType {
+	seen := map[WalletType]bool{}
+	for m, asset := range MarketAssetMapping {
+		seen[asset] = true
+		seen[MarketCurrencyMapping[m]] = true
+	}
+
+	wallets := make([]WalletType, 0, len(seen))
+	for w := range seen {
+		wallets = append(wallets, w)
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i] < wallets[j] })
+	return wallets
+}
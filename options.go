@@ -0,0 +1,211 @@
+package cryptomkt
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures a Client
+type Option func(*Client)
+
+// Limiter throttles the requests made by a Client, *rate.Limiter from
+// golang.org/x/time/rate satisfies it
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithHTTPClient makes the Client send its requests through a copy of hc, for proxies or
+// instrumentation. If hc has no timeout, the copy keeps the one already set, like the timeout of
+// NewClient. Options that change the transport or the timeout, applied after it, change the copy
+// and leave hc untouched.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc == nil {
+			return
+		}
+		cp := *hc
+		if cp.Timeout == 0 && c.client != nil {
+			cp.Timeout = c.client.Timeout
+		}
+		c.client = &cp
+	}
+}
+
+// WithBaseURL makes the Client send its requests to the API at base instead of CryptoMKT, like a
+// mock server in tests. base is the root of the API, before its version, like https://api.cryptomkt.com/
+func WithBaseURL(base string) Option {
+	return func(c *Client) {
+		if !strings.HasSuffix(base, "/") {
+			base += "/"
+		}
+		c.baseURL = base
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request
+func WithUserAgent(ua string) Option {
+	return func(c *Client) {
+		c.userAgent = ua
+	}
+}
+
+// WithTimeout bounds every request, from connecting until its body is read, to d. Zero means no timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.client.Timeout = d
+	}
+}
+
+// WithRateLimiter makes every request wait on l before being sent, so a Client shared
+// by several goroutines stays within the CryptoMKT quota. No limiter is set by default,
+// rate.NewLimiter(rate.Every(time.Second), 1) is a conservative starting point that
+// can be raised up to the quota of the account.
+func WithRateLimiter(l Limiter) Option {
+	return func(c *Client) {
+		c.limiter = l
+	}
+}
+
+// WithValidateOnStart makes New perform an authenticated call, so wrong credentials
+// fail when the Client is created instead of on its first private request
+func WithValidateOnStart() Option {
+	return func(c *Client) {
+		c.validateOnStart = true
+	}
+}
+
+// WithCompression asks the API for gzip or deflate compressed responses. The default transport
+// already does it on its own, this is for custom transports that don't.
+func WithCompression() Option {
+	return func(c *Client) {
+		c.compression = true
+	}
+}
+
+// WithMaxResponseSize sets the largest response body, in bytes, that the Client reads before
+// giving up with ErrResponseTooLarge. It defaults to 10MB.
+func WithMaxResponseSize(n int64) Option {
+	return func(c *Client) {
+		c.maxResponseSize = n
+	}
+}
+
+// WithRequestTimeout bounds each request, including the read of its body, to d. It's applied on
+// top of the context of the request and of the timeout of the underlying http.Client.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = d
+	}
+}
+
+// WithRetry makes the Client retry failed reads up to attempts times in total, waiting backoff
+// before the first retry and doubling it after each one. Only network errors, including bodies
+// cut short with ErrTruncatedResponse, 429 and 5xx responses are retried. Orders are never
+// retried: the API has no idempotency keys, so repeating the request of CreateOrder, CancelOrder
+// or InstantCreate could place or cancel an order twice.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retryAttempts = attempts
+		c.retryBackoff = backoff
+	}
+}
+
+// WithTransport makes the Client send its requests through rt
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.client.Transport = rt
+	}
+}
+
+// WithTransportTimeouts sets the timeouts of each phase of a request separately: establishing the
+// connection, the TLS handshake, and waiting for the response headers once the request is sent.
+// Zero values leave the defaults of http.DefaultTransport. The read of the body is only bounded by
+// the timeout given to NewClient or WithTimeout, so it can be kept long for large pages while
+// connecting fails fast.
+func WithTransportTimeouts(dial, tlsHandshake, responseHeader time.Duration) Option {
+	return func(c *Client) {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		if dial > 0 {
+			t.DialContext = (&net.Dialer{Timeout: dial, KeepAlive: 30 * time.Second}).DialContext
+		}
+		if tlsHandshake > 0 {
+			t.TLSHandshakeTimeout = tlsHandshake
+		}
+		if responseHeader > 0 {
+			t.ResponseHeaderTimeout = responseHeader
+		}
+		c.client.Transport = t
+	}
+}
+
+// WithHTTP2 makes the Client attempt HTTP/2 even on transports that wouldn't negotiate it, like
+// those with a custom TLS config, so concurrent requests share one connection. The protocol is
+// agreed with the server during the TLS handshake and falls back to HTTP/1.1 when the API doesn't
+// offer it. It applies to the transport set so far, so it goes after WithTransport or
+// WithTransportTimeouts, and does nothing on a RoundTripper that isn't an *http.Transport.
+func WithHTTP2() Option {
+	return func(c *Client) {
+		var t *http.Transport
+		switch rt := c.client.Transport.(type) {
+		case nil:
+			t = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			t = rt.Clone()
+		default:
+			return
+		}
+		t.ForceAttemptHTTP2 = true
+		c.client.Transport = t
+	}
+}
+
+// WithMaxConcurrency caps the requests of the Client in flight at once to n, shared by every
+// goroutine using it. New requests wait for one to finish, until their context is done, and a
+// request holds its slot until its response has been read. It complements WithRateLimiter, which
+// bounds how often requests start but not how many are outstanding.
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.inFlight = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithMinNotional sets the minimum value, amount * price in the currency of the market, of the
+// orders of each market. The API doesn't publish these minimums, so they have to be provided.
+// CreateOrder fails with a *MinNotionalError before sending an order below them, or raises its
+// amount up to the minimum if adjust is set.
+func WithMinNotional(mins map[Market]float64, adjust bool) Option {
+	return func(c *Client) {
+		c.minNotional = mins
+		c.adjustMinNotional = adjust
+	}
+}
+
+// WithPageLimit sets the number of items requested per page by the paginated endpoints. The API
+// accepts from 20 to 100, the default, and other values make them fail with ErrLimitOutOfRange.
+func WithPageLimit(n int) Option {
+	return func(c *Client) {
+		c.perPage = n
+	}
+}
+
+// WithPageDelay makes the helpers that walk several pages, like the iterators, FullBook or
+// ExportTrades, wait d between pages. Requests already wait on the rate limiter, if one is set,
+// this spaces long walks further so they don't take the whole quota.
+func WithPageDelay(d time.Duration) Option {
+	return func(c *Client) {
+		c.pageDelay = d
+	}
+}
+
+// WithReadOnly makes the Client refuse every request that changes the account, like CreateOrder,
+// CancelOrder and InstantCreate, with ErrReadOnlyClient before anything is sent
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.readOnly = true
+	}
+}
@@ -0,0 +1,294 @@
+package cryptomkt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// cancelConfirmAttempts and cancelConfirmInterval bound the wait for a cancelled Order to stop being active
+const (
+	cancelConfirmAttempts = 10
+	cancelConfirmInterval = 500 * time.Millisecond
+)
+
+// CancelReplace moves an Order to a new amount and price. CryptoMKT can't amend orders, so the
+// Order is cancelled, confirmed to be no longer active, and a new one with the same market and
+// type is created in its place. Whatever the old Order executed before being cancelled is taken
+// out of newAmount, and if that leaves nothing no Order is created and created is nil. Once the
+// cancellation succeeds, cancelled is returned even along with an error, the old Order is already
+// off the book by then and only its replacement is missing.
+func (c Client) CancelReplace(ctx context.Context, ID string, newAmount, newPrice float64) (cancelled, created *OrderResponse, err error) {
+	cancelled, err = c.CancelOrder(ctx, ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := 0; cancelled.Data.Status == "active"; i++ {
+		if i == cancelConfirmAttempts {
+			return cancelled, nil, errors.New("order is still active after being cancelled")
+		}
+		if err = c.sleep(ctx, cancelConfirmInterval); err != nil {
+			return cancelled, nil, err
+		}
+
+		status, err := c.OrderStatus(ctx, ID)
+		if err != nil {
+			return cancelled, nil, err
+		}
+		cancelled = status
+	}
+
+	var executed float64
+	if cancelled.Data.Amount.Executed != "" {
+		executed, err = parseFloat(cancelled.Data.Amount.Executed)
+		if err != nil {
+			return cancelled, nil, fmt.Errorf("invalid executed amount %q: %s", cancelled.Data.Amount.Executed, err)
+		}
+	}
+
+	amount := newAmount - executed
+	if amount <= 0 {
+		return cancelled, nil, nil
+	}
+
+	created, err = c.CreateOrder(ctx, cancelled.Data.Market, amount, newPrice, cancelled.Data.Type)
+	if err != nil {
+		return cancelled, nil, err
+	}
+	return cancelled, created, nil
+}
+
+// orderStatusWorkers bounds the concurrent requests made by OrderStatuses
+const orderStatusWorkers = 4
+
+// OrderStatuses fetches the status of every Order in ids concurrently, with a bounded number of
+// requests in flight, and returns the Orders and the errors indexed by ID
+func (c Client) OrderStatuses(ctx context.Context, ids []string) (map[string]*Order, map[string]error) {
+	orders := make(map[string]*Order, len(ids))
+	errs := make(map[string]error)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	queue := make(chan string)
+	for i := 0; i < orderStatusWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range queue {
+				res, err := c.OrderStatus(ctx, id)
+
+				mu.Lock()
+				if err != nil {
+					errs[id] = err
+				} else {
+					orders[id] = &res.Data
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, id := range ids {
+		queue <- id
+	}
+	close(queue)
+	wg.Wait()
+
+	return orders, errs
+}
+
+// checkMinNotional returns amount, or the amount that meets the minimum notional value of market
+// if the Client is allowed to adjust it, when an order of amount at price would be below it. The
+// adjusted amount is rounded up at precision, the decimals the amount is sent with, so formatting
+// it can't take it back below the minimum.
+func (c Client) checkMinNotional(market Market, amount, price float64, precision int) (float64, error) {
+	min, ok := c.minNotional[market]
+	if !ok || amount*price >= min {
+		return amount, nil
+	}
+	if !c.adjustMinNotional || price <= 0 {
+		return 0, &MinNotionalError{Market: market, Min: min, Notional: amount * price}
+	}
+
+	scale := math.Pow10(precision)
+	return math.Ceil(min/price*scale) / scale, nil
+}
+
+// allOrders fetches every page of an orders endpoint, like ActiveOrders or ExecutedOrders
+func (c Client) allOrders(ctx context.Context, fetch func(context.Context, Market, int) (*OrdersResponse, error), market Market) ([]Order, error) {
+	var orders []Order
+	p := c.newPager(0, false)
+	for !p.done {
+		if err := p.pace(ctx); err != nil {
+			return nil, err
+		}
+		res, err := fetch(ctx, market, p.page)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, res.Data...)
+		p.advance(res.Pagination, len(res.Data))
+	}
+	return orders, nil
+}
+
+// CancelOrders cancels the active Orders of market on the side given by ot, leaving the other side
+// untouched. It returns the responses of the cancelled Orders, and an OrderErrors with the ones that
+// couldn't be cancelled.
+func (c Client) CancelOrders(ctx context.Context, market Market, ot OrderType) ([]OrderResponse, error) {
+	active, err := c.allOrders(ctx, c.ActiveOrders, market)
+	if err != nil {
+		return nil, err
+	}
+
+	var cancelled []OrderResponse
+	errs := OrderErrors{}
+	for _, o := range active {
+		if o.Type != ot {
+			continue
+		}
+		res, err := c.CancelOrder(ctx, o.ID)
+		if err != nil {
+			errs[o.ID] = err
+			continue
+		}
+		cancelled = append(cancelled, *res)
+	}
+
+	if len(errs) > 0 {
+		return cancelled, errs
+	}
+	return cancelled, nil
+}
+
+// Exposure walks the active Orders of market and adds up their notional value, remaining amount
+// times price, on each side
+func (c Client) Exposure(ctx context.Context, market Market) (buyNotional, sellNotional float64, orderCount int, err error) {
+	active, err := c.allOrders(ctx, c.ActiveOrders, market)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	for _, o := range active {
+		fs, err := o.FillSummary()
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		price, err := parseFloat(o.Price)
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid price %q: %s", o.Price, err)
+		}
+
+		if o.Type == BUY {
+			buyNotional += fs.Remaining * price
+		} else {
+			sellNotional += fs.Remaining * price
+		}
+	}
+	return buyNotional, sellNotional, len(active), nil
+}
+
+// ExecutedOrdersSince returns the executed Orders of market last updated at or after from. The API
+// can't filter executed orders by date, so their pages are walked from the newest and the walk stops
+// at the first page that only holds older Orders instead of fetching the whole history.
+func (c Client) ExecutedOrdersSince(ctx context.Context, market Market, from time.Time) ([]Order, error) {
+	var orders []Order
+	p := c.newPager(0, false)
+	for !p.done {
+		if err := p.pace(ctx); err != nil {
+			return nil, err
+		}
+		res, err := c.ExecutedOrders(ctx, market, p.page)
+		if err != nil {
+			return nil, err
+		}
+
+		recent := 0
+		for _, o := range res.Data {
+			if !o.UpdatedAt.Before(from) {
+				orders = append(orders, o)
+				recent++
+			}
+		}
+		if recent == 0 && len(res.Data) > 0 {
+			break
+		}
+		p.advance(res.Pagination, len(res.Data))
+	}
+	return orders, nil
+}
+
+// CreateOrderWithFallback is a convenience over PlaceOrder for sizing orders to the funds at hand:
+// when an order is rejected with ErrInsufficientFunds, its amount is reduced by reduceFraction,
+// 0.1 takes 10% off, and it's placed again, up to maxAttempts in total. A rejected order isn't
+// placed, so retrying it can't create it twice. Any other error is returned right away, and after
+// the last attempt the error of the smallest order is.
+func (c Client) CreateOrderWithFallback(ctx context.Context, req CreateOrderRequest, reduceFraction float64, maxAttempts int) (*OrderResponse, error) {
+	if reduceFraction <= 0 || reduceFraction >= 1 {
+		return nil, fmt.Errorf("reduce fraction %g must be between 0 and 1", reduceFraction)
+	}
+
+	var err error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var res *OrderResponse
+		res, err = c.PlaceOrder(ctx, req)
+		if !errors.Is(err, ErrInsufficientFunds) {
+			return res, err
+		}
+		req.Amount *= 1 - reduceFraction
+	}
+	if err == nil {
+		err = fmt.Errorf("max attempts %d must be positive", maxAttempts)
+	}
+	return nil, err
+}
+
+// AllOrders returns the active and the executed Orders of market in one list, sorted by UpdatedAt,
+// oldest first. Both are fetched in full, and an Order that moved from active to executed while
+// they were being fetched appears once, as its latest update.
+func (c Client) AllOrders(ctx context.Context, market Market) ([]Order, error) {
+	active, err := c.allOrders(ctx, c.ActiveOrders, market)
+	if err != nil {
+		return nil, err
+	}
+	executed, err := c.allOrders(ctx, c.ExecutedOrders, market)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]Order, len(active)+len(executed))
+	for _, o := range append(active, executed...) {
+		if seen, ok := byID[o.ID]; !ok || !o.UpdatedAt.Before(seen.UpdatedAt.Time) {
+			byID[o.ID] = o
+		}
+	}
+
+	orders := make([]Order, 0, len(byID))
+	for _, o := range byID {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].UpdatedAt.Equal(orders[j].UpdatedAt.Time) {
+			return orders[i].ID < orders[j].ID
+		}
+		return orders[i].UpdatedAt.Before(orders[j].UpdatedAt.Time)
+	})
+	return orders, nil
+}
+
+// BreakEvenPrice returns the price at which closing a position opened with an order of type ot at
+// entryPrice makes up for the fees of both orders, feeRate being the fee of each as a fraction, 0.005
+// for 0.5%. A BUY entry has to be sold at entryPrice * (1 + feeRate) / (1 - feeRate) and a SELL
+// entry bought back at entryPrice * (1 - feeRate) / (1 + feeRate). The API doesn't publish its fee
+// schedule, so feeRate has to be provided.
+func BreakEvenPrice(entryPrice float64, ot OrderType, feeRate float64) float64 {
+	if ot == SELL {
+		return entryPrice * (1 - feeRate) / (1 + feeRate)
+	}
+	return entryPrice * (1 + feeRate) / (1 - feeRate)
+}
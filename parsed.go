@@ -0,0 +1,179 @@
+package cryptomkt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ParseErrors holds the errors of parsing the fields of a response type, indexed by field name
+type ParseErrors map[string]error
+
+func (e ParseErrors) Error() string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	msgs := make([]string, len(fields))
+	for i, field := range fields {
+		msgs[i] = fmt.Sprintf("%s: %s", field, e[field])
+	}
+	return "invalid fields: " + strings.Join(msgs, "; ")
+}
+
+// fieldParser parses the number fields of a response type, collecting the errors of every field
+type fieldParser struct {
+	errs ParseErrors
+}
+
+func (p *fieldParser) float(field, s string) float64 {
+	f, err := parseFloat(s)
+	if err != nil {
+		if p.errs == nil {
+			p.errs = ParseErrors{}
+		}
+		p.errs[field] = err
+	}
+	return f
+}
+
+// optional parses a field that the API may omit, which is zero when absent
+func (p *fieldParser) optional(field, s string) float64 {
+	if s == "" {
+		return 0
+	}
+	return p.float(field, s)
+}
+
+func (p *fieldParser) err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return p.errs
+}
+
+// ParsedTicker is a Ticker with its numbers parsed
+type ParsedTicker struct {
+	High      float64
+	Volume    float64
+	Low       float64
+	Ask       float64
+	Timestamp time.Time
+	Bid       float64
+	LastPrice float64
+	Market    Market
+}
+
+// Parse returns the Ticker with its numbers parsed, or ParseErrors with the fields that failed
+func (t Ticker) Parse() (ParsedTicker, error) {
+	var p fieldParser
+	parsed := ParsedTicker{
+		High:      p.float("High", t.High),
+		Volume:    p.float("Volume", t.Volume),
+		Low:       p.float("Low", t.Low),
+		Ask:       p.float("Ask", t.Ask),
+		Timestamp: t.Timestamp.Time,
+		Bid:       p.float("Bid", t.Bid),
+		LastPrice: p.float("LastPrice", t.LastPrice),
+		Market:    t.Market,
+	}
+	return parsed, p.err()
+}
+
+// ParsedBookOrder is an OrderBookOrder with its numbers parsed
+type ParsedBookOrder struct {
+	Timestamp time.Time
+	Price     float64
+	Amount    float64
+}
+
+// Parse returns the OrderBookOrder with its numbers parsed, or ParseErrors with the fields that failed
+func (o OrderBookOrder) Parse() (ParsedBookOrder, error) {
+	var p fieldParser
+	parsed := ParsedBookOrder{
+		Timestamp: o.Timestamp.Time,
+		Price:     p.float("Price", o.Price),
+		Amount:    p.float("Amount", o.Amount),
+	}
+	return parsed, p.err()
+}
+
+// ParsedTrade is a Trade with its numbers parsed
+type ParsedTrade struct {
+	MarketTaker OrderType
+	Timestamp   time.Time
+	Price       float64
+	Amount      float64
+	Market      Market
+}
+
+// Parse returns the Trade with its numbers parsed, or ParseErrors with the fields that failed
+func (t Trade) Parse() (ParsedTrade, error) {
+	var p fieldParser
+	parsed := ParsedTrade{
+		MarketTaker: t.MarketTaker,
+		Timestamp:   t.Timestamp.Time,
+		Price:       p.float("Price", t.Price),
+		Amount:      p.float("Amount", t.Amount),
+		Market:      t.Market,
+	}
+	return parsed, p.err()
+}
+
+// ParsedOrder is an Order with its numbers parsed. The optional fields that the API omits
+// are zero.
+type ParsedOrder struct {
+	Status            string
+	CreatedAt         time.Time
+	Original          float64
+	Remaining         float64
+	Executed          float64
+	ExecutionPrice    float64
+	AvgExecutionPrice float64
+	Price             float64
+	Type              OrderType
+	ID                string
+	Market            Market
+	UpdatedAt         time.Time
+}
+
+// Parse returns the Order with its numbers parsed, or ParseErrors with the fields that failed
+func (o Order) Parse() (ParsedOrder, error) {
+	var p fieldParser
+	parsed := ParsedOrder{
+		Status:            o.Status,
+		CreatedAt:         o.CreatedAt.Time,
+		Original:          p.float("Amount.Original", o.Amount.Original),
+		Remaining:         p.optional("Amount.Remaining", o.Amount.Remaining),
+		Executed:          p.optional("Amount.Executed", o.Amount.Executed),
+		ExecutionPrice:    p.optional("ExecutionPrice", o.ExecutionPrice),
+		AvgExecutionPrice: p.optional("AvgExecutionPrice", o.AvgExecutionPrice),
+		Price:             p.float("Price", o.Price),
+		Type:              o.Type,
+		ID:                o.ID,
+		Market:            o.Market,
+		UpdatedAt:         o.UpdatedAt.Time,
+	}
+	return parsed, p.err()
+}
+
+// ParsedWallet is a Wallet with its numbers parsed
+type ParsedWallet struct {
+	Available float64
+	Wallet    WalletType
+	Balance   float64
+}
+
+// Parse returns the Wallet with its numbers parsed, or ParseErrors with the fields that failed
+func (w Wallet) Parse() (ParsedWallet, error) {
+	var p fieldParser
+	parsed := ParsedWallet{
+		Available: p.float("Available", w.Available),
+		Wallet:    w.Wallet,
+		Balance:   p.float("Balance", w.Balance),
+	}
+	return parsed, p.err()
+}
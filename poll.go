@@ -0,0 +1,128 @@
+package cryptomkt
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PollOption configures PollTicker
+type PollOption func(*pollConfig)
+
+type pollConfig struct {
+	staleAfter time.Duration
+}
+
+// StaleAfter makes PollTicker report an error wrapping ErrStaleTicker on every poll once the
+// Timestamp of the Ticker hasn't advanced for longer than d
+func StaleAfter(d time.Duration) PollOption {
+	return func(cfg *pollConfig) {
+		cfg.staleAfter = d
+	}
+}
+
+// PollTicker polls the Ticker of market every interval and sends it on the returned channel,
+// as a stream for when the socket API isn't reachable. Failed polls are skipped and their error
+// is sent on the error channel if there's room for it. Both channels are closed once ctx is done.
+func (c Client) PollTicker(ctx context.Context, market Market, interval time.Duration, opts ...PollOption) (<-chan Ticker, <-chan error) {
+	var cfg pollConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tickers := make(chan Ticker)
+	errs := make(chan error, 1)
+	report := func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	}
+
+	go func() {
+		defer close(tickers)
+		defer close(errs)
+
+		var last time.Time
+		lastAdvance := c.now()
+
+		for {
+			res, err := c.Ticker(ctx, market)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				report(err)
+			} else {
+				for _, ticker := range res.Data {
+					if ticker.Timestamp.After(last) {
+						last = ticker.Timestamp.Time
+						lastAdvance = c.now()
+					} else if stalled := c.now().Sub(lastAdvance); cfg.staleAfter > 0 && stalled > cfg.staleAfter {
+						report(fmt.Errorf("ticker of %s hasn't changed in %s: %w", market, stalled, ErrStaleTicker))
+					}
+
+					select {
+					case tickers <- ticker:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+
+			select {
+			case <-c.after(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return tickers, errs
+}
+
+// WaitForSpread polls the Ticker of market every interval until its spread, as a percentage of
+// the midpoint, is at most maxSpreadPercent, and returns the Ticker that met it. Failed polls
+// are retried on the next interval, ctx bounds the whole wait.
+func (c Client) WaitForSpread(ctx context.Context, market Market, maxSpreadPercent float64, interval time.Duration) (Ticker, error) {
+	for {
+		res, err := c.Ticker(ctx, market)
+		if err == nil {
+			for _, ticker := range res.Data {
+				spread, err := ticker.SpreadPercent()
+				if err == nil && spread <= maxSpreadPercent {
+					return ticker, nil
+				}
+			}
+		}
+
+		select {
+		case <-c.after(interval):
+		case <-ctx.Done():
+			return Ticker{}, ctx.Err()
+		}
+	}
+}
+
+// WaitForBalance polls the Balance every interval until the Available amount of wallet is at
+// least minAvailable, and returns the Wallet that met it. Failed polls are retried on the next
+// interval, ctx bounds the whole wait.
+func (c Client) WaitForBalance(ctx context.Context, wallet WalletType, minAvailable float64, interval time.Duration) (*Wallet, error) {
+	for {
+		res, err := c.Balance(ctx)
+		if err == nil {
+			if w, ok := res.Get(wallet); ok {
+				available, err := w.AvailableFloat()
+				if err == nil && available >= minAvailable {
+					return &w, nil
+				}
+			}
+		}
+
+		select {
+		case <-c.after(interval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
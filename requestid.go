@@ -0,0 +1,46 @@
+package cryptomkt
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+// RequestIDHeader is the header that carries the ID of each request
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx that makes the requests of the Client use id as
+// their ID, instead of a generated one, so they can be traced with the rest of an operation
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// setRequestID tags req with the ID from its context, or with a new random UUID
+func setRequestID(req *http.Request) string {
+	id, _ := req.Context().Value(requestIDKey{}).(string)
+	if id == "" {
+		id = newRequestID()
+	}
+	req.Header.Set(RequestIDHeader, id)
+	return id
+}
+
+// requestID returns the ID of the request that res answers
+func requestID(res *http.Response) string {
+	if res.Request == nil {
+		return ""
+	}
+	return res.Request.Header.Get(RequestIDHeader)
+}
+
+// newRequestID returns a random version 4 UUID
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
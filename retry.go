@@ -0,0 +1,39 @@
+package cryptomkt
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// retry calls do up to the attempts set with WithRetry while it fails with a retryable error,
+// doubling the backoff between attempts. It must only wrap idempotent requests.
+func (c Client) retry(ctx context.Context, do func() (*http.Response, error)) (*http.Response, error) {
+	res, err := do()
+	backoff := c.retryBackoff
+	for attempt := 1; attempt < c.retryAttempts && retryable(ctx, res, err); attempt++ {
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.after(backoff):
+		}
+		backoff *= 2
+
+		res, err = do()
+	}
+	return res, err
+}
+
+// retryable reports whether a request that ended with res and err may succeed if repeated
+func retryable(ctx context.Context, res *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil
+	}
+	return res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError
+}
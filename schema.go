@@ -0,0 +1,138 @@
+package cryptomkt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SchemaIssue describes a field of an API response that doesn't match what the package expects
+type SchemaIssue struct {
+	Endpoint string
+	Field    string
+	Problem  string
+}
+
+func (i SchemaIssue) String() string {
+	return fmt.Sprintf("%s: %s %s", i.Endpoint, i.Field, i.Problem)
+}
+
+// fieldKind is the JSON type expected for a field
+type fieldKind string
+
+const (
+	kindString fieldKind = "string"
+	kindNumber fieldKind = "number"
+	// kindFlexInt is a number that may come as a string, see FlexInt
+	kindFlexInt fieldKind = "number or string"
+)
+
+var (
+	paginationSchema = map[string]fieldKind{"previous": kindFlexInt, "limit": kindFlexInt, "page": kindFlexInt, "next": kindFlexInt}
+	tickerSchema     = map[string]fieldKind{"high": kindString, "volume": kindString, "low": kindString, "ask": kindString, "timestamp": kindString, "bid": kindString, "last_price": kindString, "market": kindString}
+	bookSchema       = map[string]fieldKind{"timestamp": kindString, "price": kindString, "amount": kindString}
+	tradeSchema      = map[string]fieldKind{"market_taker": kindString, "timestamp": kindString, "price": kindString, "amount": kindString, "market": kindString}
+	candleSchema     = map[string]fieldKind{"candle_id": kindFlexInt, "open_price": kindString, "hight_price": kindString, "close_price": kindString, "low_price": kindString, "volume_sum": kindString, "candle_date": kindString, "tick_count": kindFlexInt}
+	walletSchema     = map[string]fieldKind{"available": kindString, "wallet": kindString, "balance": kindString}
+)
+
+// VerifySchema requests a sample of each endpoint and checks that the fields the package decodes
+// are still there with the expected types, to catch changes of the API early. Private endpoints
+// are only checked when the Client has credentials. It makes several requests, so it's meant for
+// CI or startup checks rather than regular use. The error is only set when a request fails.
+func (c Client) VerifySchema(ctx context.Context) ([]SchemaIssue, error) {
+	market := ETHCLP
+	markets, err := c.Markets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(markets.Data) > 0 {
+		market = markets.Data[0]
+	}
+
+	now := c.now()
+	samples := []struct {
+		path   string
+		params map[string]string
+		auth   bool
+		check  func(v *schemaVerifier, sample map[string]interface{})
+	}{
+		{"ticker", map[string]string{"market": string(market)}, false, func(v *schemaVerifier, sample map[string]interface{}) {
+			v.first("data", sample["data"], tickerSchema)
+		}},
+		{"book", map[string]string{"market": string(market), "type": string(BUY)}, false, func(v *schemaVerifier, sample map[string]interface{}) {
+			v.object("pagination", sample["pagination"], paginationSchema)
+			v.first("data", sample["data"], bookSchema)
+		}},
+		{"trades", map[string]string{"market": string(market), "start": now.AddDate(0, 0, -7).Format(tradesDateLayout), "end": now.Format(tradesDateLayout)}, false, func(v *schemaVerifier, sample map[string]interface{}) {
+			v.object("pagination", sample["pagination"], paginationSchema)
+			v.first("data", sample["data"], tradeSchema)
+		}},
+		{"prices", map[string]string{"market": string(market), "timeframe": string(TF1h)}, false, func(v *schemaVerifier, sample map[string]interface{}) {
+			data, _ := sample["data"].(map[string]interface{})
+			v.first("data.ask", data["ask"], candleSchema)
+			v.first("data.bid", data["bid"], candleSchema)
+		}},
+		{"balance", nil, true, func(v *schemaVerifier, sample map[string]interface{}) {
+			v.first("data", sample["data"], walletSchema)
+		}},
+	}
+
+	var issues []SchemaIssue
+	for _, s := range samples {
+		if s.auth && c.key == "" {
+			continue
+		}
+
+		var sample map[string]interface{}
+		if err := c.Do(ctx, http.MethodGet, s.path, s.params, s.auth, &sample); err != nil {
+			return nil, err
+		}
+		v := &schemaVerifier{endpoint: s.path}
+		s.check(v, sample)
+		issues = append(issues, v.issues...)
+	}
+	return issues, nil
+}
+
+// schemaVerifier collects the SchemaIssues of a sample of an endpoint
+type schemaVerifier struct {
+	endpoint string
+	issues   []SchemaIssue
+}
+
+// first checks the first element of the array at name, if it has any
+func (v *schemaVerifier) first(name string, value interface{}, schema map[string]fieldKind) {
+	list, ok := value.([]interface{})
+	if !ok {
+		v.issues = append(v.issues, SchemaIssue{v.endpoint, name, "is not an array"})
+		return
+	}
+	if len(list) > 0 {
+		v.object(name+"[0]", list[0], schema)
+	}
+}
+
+// object checks that the object at name has every field of schema with its expected kind
+func (v *schemaVerifier) object(name string, value interface{}, schema map[string]fieldKind) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		v.issues = append(v.issues, SchemaIssue{v.endpoint, name, "is not an object"})
+		return
+	}
+
+	for field, kind := range schema {
+		got, ok := obj[field]
+		if !ok {
+			v.issues = append(v.issues, SchemaIssue{v.endpoint, name + "." + field, "is missing"})
+			continue
+		}
+
+		_, isString := got.(string)
+		_, isNumber := got.(json.Number)
+		if (kind == kindString && !isString) || (kind == kindNumber && !isNumber) || (kind == kindFlexInt && !isString && !isNumber && got != nil) {
+			v.issues = append(v.issues, SchemaIssue{v.endpoint, name + "." + field, fmt.Sprintf("is %T, expected %s", got, kind)})
+		}
+	}
+}
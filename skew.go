@@ -0,0 +1,51 @@
+package cryptomkt
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// clockSkew holds the offset of the server clock from the local one, in nanoseconds, shared by
+// the copies of a Client
+type clockSkew struct {
+	offset int64
+}
+
+// WithClockSkewCorrection makes the Client measure how far the clock of the API is from the local
+// one, from the Date header of every response, and sign requests with its timestamp shifted by
+// that offset, so they stay within the window the API accepts on machines with drifting clocks.
+// The Date header only has a resolution of one second, so offsets below it are ignored, and
+// requests signed before the first response use the local clock.
+func WithClockSkewCorrection() Option {
+	return func(c *Client) {
+		c.skew = &clockSkew{}
+	}
+}
+
+// ClockSkew returns the offset of the API clock from the local one measured with
+// WithClockSkewCorrection, positive when the API is ahead, or 0 if it isn't set
+func (c Client) ClockSkew() time.Duration {
+	if c.skew == nil {
+		return 0
+	}
+	return time.Duration(atomic.LoadInt64(&c.skew.offset))
+}
+
+// observeSkew updates the clock offset with the Date header of res
+func (c Client) observeSkew(res *http.Response) {
+	if c.skew == nil {
+		return
+	}
+	date, err := http.ParseTime(res.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+
+	// The Date is truncated to the second, so the server time is half a second past it on average
+	offset := date.Add(500 * time.Millisecond).Sub(c.now())
+	if offset > -time.Second && offset < time.Second {
+		offset = 0
+	}
+	atomic.StoreInt64(&c.skew.offset, int64(offset))
+}
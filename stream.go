@@ -0,0 +1,207 @@
+package cryptomkt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// EachTrade walks every page of the Trades of market between start and end, like IterateTrades,
+// but decodes the Data of each page one Trade at a time, as the response arrives, and passes it to
+// fn instead of collecting it in a slice, so exports of any size run in constant memory. It stops
+// at the first error of fn, which is returned, and ctx is checked between pages. A page cut short
+// fails with ErrTruncatedResponse instead of being retried, since fn already got part of it.
+func (c Client) EachTrade(ctx context.Context, market Market, start, end string, fn func(Trade) error) error {
+	for _, date := range []string{start, end} {
+		if err := checkTradesDate(date); err != nil {
+			return err
+		}
+	}
+
+	params := map[string]string{"market": string(market), "start": start, "end": end}
+	return c.each(ctx, "trades", params, false, func(dec *json.Decoder) error {
+		var t Trade
+		if err := dec.Decode(&t); err != nil {
+			return err
+		}
+		return callback(fn(t))
+	})
+}
+
+// EachExecutedOrder walks every page of the executed Orders of market like EachTrade does with
+// the Trades, passing them to fn one at a time
+func (c Client) EachExecutedOrder(ctx context.Context, market Market, fn func(Order) error) error {
+	params := map[string]string{"market": string(market)}
+	return c.each(ctx, "orders/executed", params, true, func(dec *json.Decoder) error {
+		var o Order
+		if err := dec.Decode(&o); err != nil {
+			return err
+		}
+		return callback(fn(o))
+	})
+}
+
+// each requests every page of the paginated endpoint at path and streams the items of their Data
+// through item, see decodeEach
+func (c Client) each(ctx context.Context, path string, params map[string]string, auth bool, item func(dec *json.Decoder) error) error {
+	if err := c.checkPage(0); err != nil {
+		return err
+	}
+
+	p := c.newPager(0, false)
+	for !p.done {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := p.pace(ctx); err != nil {
+			return err
+		}
+
+		page := make(map[string]string, len(params)+2)
+		for k, v := range params {
+			page[k] = v
+		}
+		page["page"] = strconv.Itoa(p.page)
+		page["limit"] = strconv.Itoa(p.limit)
+
+		res, err := c.getStream(ctx, path, page, auth)
+		if err != nil {
+			return err
+		}
+		pagination, n, err := c.decodeEach(res, path, item)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+		p.advance(pagination, n)
+	}
+	return nil
+}
+
+// callbackError marks the errors of the callbacks of EachTrade and EachExecutedOrder, which
+// decodeEach returns as they are instead of reporting them as decoding errors
+type callbackError struct{ err error }
+
+func (e callbackError) Error() string { return e.err.Error() }
+
+// callback wraps a non-nil err of a callback in a callbackError
+func callback(err error) error {
+	if err != nil {
+		return callbackError{err}
+	}
+	return nil
+}
+
+// decodeEach decodes the response of path like decode, but it walks the Data array token by token
+// and calls item with the Decoder positioned at each element, so only one is decoded at a time. It
+// returns the Pagination of the response and the number of elements. Like decode, it fails with
+// ErrResponseTooLarge once the body goes over the size limit.
+func (c Client) decodeEach(res *http.Response, path string, item func(dec *json.Decoder) error) (Pagination, int, error) {
+	var body *io.LimitedReader
+	fail := func(op string, err error) error {
+		if body != nil && body.N <= 0 {
+			op, err = "read", ErrResponseTooLarge
+		}
+		return fmt.Errorf("%s %s (%d, request %s): %w", op, path, res.StatusCode, requestID(res), err)
+	}
+
+	// Failed requests carry no Data, decode reports their error
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		if err := c.decode(res, path, &struct{}{}); err != nil {
+			return Pagination{}, 0, err
+		}
+		return Pagination{}, 0, fail("decode", errors.New("successful status on a failed response"))
+	}
+
+	r, err := responseBody(res)
+	if err != nil {
+		return Pagination{}, 0, fail("read", err)
+	}
+	defer r.Close()
+
+	body = &io.LimitedReader{R: r, N: c.responseLimit() + 1}
+	dec := json.NewDecoder(body)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		if underMaintenance(res, nil) {
+			return Pagination{}, 0, &MaintenanceError{RetryAfter: c.retryAfter(res)}
+		}
+		if err == nil {
+			err = fmt.Errorf("expected an object, got %v", tok)
+		}
+		return Pagination{}, 0, fail("decode", truncated(err))
+	}
+
+	var status, message string
+	var pagination Pagination
+	n := 0
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Pagination{}, n, fail("decode", truncated(err))
+		}
+
+		switch key, _ := tok.(string); strings.ToLower(key) {
+		case "status":
+			err = dec.Decode(&status)
+		case "message":
+			err = dec.Decode(&message)
+		case "pagination":
+			err = dec.Decode(&pagination)
+		case "data":
+			err = decodeArray(dec, func() error {
+				n++
+				return item(dec)
+			})
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+
+		if e, ok := err.(callbackError); ok {
+			return Pagination{}, n, e.err
+		}
+		if err != nil {
+			return Pagination{}, n, fail("decode", truncated(err))
+		}
+	}
+
+	if body.N <= 0 {
+		return Pagination{}, n, fail("read", ErrResponseTooLarge)
+	}
+	if !IsSuccess(status) {
+		if underMaintenance(res, []byte(message)) {
+			return Pagination{}, n, &MaintenanceError{RetryAfter: c.retryAfter(res)}
+		}
+		return Pagination{}, n, &APIError{Status: status, Message: message, StatusCode: res.StatusCode, RequestID: requestID(res)}
+	}
+	return pagination, n, nil
+}
+
+// decodeArray reads the array at the position of dec and calls item for each of its elements,
+// which must consume them. A null array has no elements.
+func decodeArray(dec *json.Decoder, item func() error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("expected an array, got %v", tok)
+	}
+
+	for dec.More() {
+		if err := item(); err != nil {
+			return err
+		}
+	}
+	_, err = dec.Token()
+	return err
+}
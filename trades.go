@@ -0,0 +1,117 @@
+package cryptomkt
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// tradesDateLayout is the date format expected by the Trades endpoint
+const tradesDateLayout = "2006-01-02"
+
+// Export formats supported by ExportTrades
+const (
+	ExportNDJSON = "ndjson"
+	ExportCSV    = "csv"
+)
+
+// ExportTrades pages through the Trades of market between start and end, both included, and writes
+// each of them to w as they arrive, either as NDJSON or CSV, so the whole dataset is never held in
+// memory. The API only filters by day, so the Trades of the days of start and end outside of them
+// are left out. ctx is checked between pages.
+func (c Client) ExportTrades(ctx context.Context, market Market, start, end time.Time, w io.Writer, format string) error {
+	var write func(t Trade) error
+	var flush func() error
+	switch format {
+	case ExportNDJSON:
+		enc := json.NewEncoder(w)
+		write = func(t Trade) error { return enc.Encode(t) }
+		flush = func() error { return nil }
+	case ExportCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write([]string{"market", "market_taker", "timestamp", "price", "amount"}); err != nil {
+			return err
+		}
+		write = func(t Trade) error {
+			return cw.Write([]string{string(t.Market), string(t.MarketTaker), t.Timestamp.Format(time.RFC3339Nano), t.Price, t.Amount})
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		return fmt.Errorf("unknown export format %q", format)
+	}
+
+	from, to := start.UTC().Format(tradesDateLayout), end.UTC().AddDate(0, 0, 1).Format(tradesDateLayout)
+	err := c.EachTrade(ctx, market, from, to, func(t Trade) error {
+		if t.Timestamp.Before(start) || t.Timestamp.After(end) {
+			return nil
+		}
+		return write(t)
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// recentTradesMaxDays bounds how far back RecentTrades looks for trades
+const recentTradesMaxDays = 30
+
+// RecentTrades returns up to the n latest Trades of market, newest first, from the last 30 days,
+// so no date range is needed. The Trades endpoint returns them newest first, so its pages are
+// walked from the latest one and the walk stops as soon as n Trades are found.
+func (c Client) RecentTrades(ctx context.Context, market Market, n int) ([]Trade, error) {
+	var trades []Trade
+	today := c.now().UTC()
+	start, end := today.AddDate(0, 0, 1-recentTradesMaxDays).Format(tradesDateLayout), today.AddDate(0, 0, 1).Format(tradesDateLayout)
+	it := c.IterateTrades(ctx, market, start, end, 0, false)
+	for len(trades) < n && it.Next() {
+		trades = append(trades, it.Trades()...)
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(trades) > n {
+		trades = trades[:n]
+	}
+	return trades, nil
+}
+
+// TradesSince returns the Trades of market newer than since, oldest first, to follow the tape
+// from the last Trade seen. The Trades endpoint returns them newest first, so its pages are walked
+// from the latest one and the walk stops at the first Trade at or before since, fetching only the
+// new ones. since must be set and within the last 30 days, like the window of RecentTrades.
+func (c Client) TradesSince(ctx context.Context, market Market, since time.Time) ([]Trade, error) {
+	now := c.now()
+	if since.IsZero() || now.Sub(since) > recentTradesMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("since %s must be within the last %d days", since.Format(time.RFC3339), recentTradesMaxDays)
+	}
+
+	var trades []Trade
+	start, end := since.UTC().Format(tradesDateLayout), now.UTC().AddDate(0, 0, 1).Format(tradesDateLayout)
+	it := c.IterateTrades(ctx, market, start, end, 0, false)
+walk:
+	for it.Next() {
+		for _, t := range it.Trades() {
+			if !t.Timestamp.After(since) {
+				break walk
+			}
+			trades = append(trades, t)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Timestamp.Before(trades[j].Timestamp.Time)
+	})
+	return trades, nil
+}
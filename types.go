@@ -2,8 +2,13 @@ package cryptomkt
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,28 +17,118 @@ type Client struct {
 	key    string
 	secret string
 	client *http.Client
+
+	baseURL   string
+	userAgent string
+
+	limiter         Limiter
+	validateOnStart bool
+	compression     bool
+	maxResponseSize int64
+	requestTimeout  time.Duration
+	retryAttempts   int
+	retryBackoff    time.Duration
+	perPage         int
+	pageDelay       time.Duration
+	readOnly        bool
+	clock           Clock
+	inFlight        chan struct{}
+	skew            *clockSkew
+
+	minNotional       map[Market]float64
+	adjustMinNotional bool
 }
 
-// FlexInt is a fix for a wrong return on the API, where "null" is returned instead of null
+// FlexInt is a fix for a wrong return on the API, where "null" is returned instead of null,
+// and numbers may come quoted or as floats like 100.0
 type FlexInt int
 
-// UnmarshalJSON parses inconsistent int || "null" value from CryptoMKT
+// UnmarshalJSON parses inconsistent int || float || "int" || "null" value from CryptoMKT
 func (fi *FlexInt) UnmarshalJSON(b []byte) error {
-	if b[0] != '"' {
-		return json.Unmarshal(b, (*int)(fi))
+	if string(b) == "null" {
+		return nil
 	}
-	var s string
-	if err := json.Unmarshal(b, &s); err != nil {
-		return err
+
+	quoted := b[0] == '"'
+	s := string(b)
+	if quoted {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
 	}
-	i, err := strconv.Atoi(s)
-	if err != nil {
-		return nil
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != math.Trunc(f) {
+		if quoted {
+			// Quoted values that aren't numbers, like "null", are left unset
+			return nil
+		}
+		return fmt.Errorf("invalid integer %s", b)
 	}
-	*fi = FlexInt(i)
+	*fi = FlexInt(f)
 	return nil
 }
 
+// parseFloat parses the decimal strings returned by CryptoMKT. It tolerates locale formatted
+// numbers like "1.234,56": when both separators appear the last one is the decimal separator and
+// the other one groups thousands, and a separator that appears more than once groups thousands.
+// A single comma between 1 to 3 digits without a leading zero and exactly three digits, like
+// "1,000", could be either, so it's an error, while "0,125" can only be a decimal.
+func parseFloat(number string) (float64, error) {
+	s := strings.TrimSpace(number)
+
+	lastComma, lastDot := strings.LastIndex(s, ","), strings.LastIndex(s, ".")
+	switch {
+	case lastComma >= 0 && lastDot >= 0 && lastComma > lastDot:
+		s = strings.Replace(strings.Replace(s, ".", "", -1), ",", ".", 1)
+	case lastComma >= 0 && lastDot >= 0:
+		s = strings.Replace(s, ",", "", -1)
+	case strings.Count(s, ",") > 1:
+		s = strings.Replace(s, ",", "", -1)
+	case lastComma >= 0:
+		whole, frac := strings.TrimLeft(s[:lastComma], "+-"), s[lastComma+1:]
+		if len(whole) >= 1 && len(whole) <= 3 && whole[0] != '0' && isDigits(whole) && len(frac) == 3 && isDigits(frac) {
+			return 0, fmt.Errorf("ambiguous number %q", number)
+		}
+		s = strings.Replace(s, ",", ".", 1)
+	case strings.Count(s, ".") > 1:
+		s = strings.Replace(s, ".", "", -1)
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", number)
+	}
+	return f, nil
+}
+
+// isDigits reports whether s is made only of ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// parseOptionalFloat is like parseFloat, but an empty string, from a field that the API omitted, is zero
+func parseOptionalFloat(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return parseFloat(s)
+}
+
+// optionalFloat parses a field that the API may omit, reporting false when it's absent or invalid
+func optionalFloat(s string) (float64, bool) {
+	if s == "" {
+		return 0, false
+	}
+	f, err := parseFloat(s)
+	return f, err == nil
+}
+
 // OrderType represents a buy or sell signal
 type OrderType string
 
@@ -63,9 +158,16 @@ type Time struct {
 	time.Time
 }
 
-// UnmarshalJSON parses custom date format from CryptoMKT
+// UnmarshalJSON parses custom date format from CryptoMKT, absent times, null or "", are left unset
 func (t *Time) UnmarshalJSON(b []byte) error {
 	s := string(b)
+	if s == "null" || s == `""` {
+		t.Time = time.Time{}
+		return nil
+	}
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return fmt.Errorf("invalid time %s, it must be a string", b)
+	}
 
 	// Get rid of the quotes "" around the value.
 	// A second option would be to include them
@@ -77,15 +179,41 @@ func (t *Time) UnmarshalJSON(b []byte) error {
 	if err != nil {
 		ts, err = time.Parse("2006-01-02T15:04:05.999999", s)
 	}
+	if err != nil {
+		ts, err = time.Parse("2006-01-02 15:04:05", s)
+	}
+	if err != nil {
+		// The format of MarshalJSON, so encoded values can be decoded back
+		ts, err = time.Parse(time.RFC3339Nano, s)
+	}
 	t.Time = ts
 	return err
 }
 
+// MarshalJSON encodes the Time in RFC 3339, or as null when it's unset
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return t.Time.MarshalJSON()
+}
+
+// IsZero reports whether the Time is unset, like the execution time of an order that hasn't
+// been filled, instead of a real date
+func (t Time) IsZero() bool {
+	return t.Time.IsZero()
+}
+
+// Value returns the time.Time, or false if it's unset
+func (t Time) Value() (time.Time, bool) {
+	return t.Time, !t.IsZero()
+}
+
 // Pagination is the representation of the CryptoMKT pagination section of the API results
 type Pagination struct {
 	Previous FlexInt
-	Limit    int
-	Page     int
+	Limit    FlexInt
+	Page     FlexInt
 	Next     FlexInt
 }
 
@@ -112,12 +240,105 @@ const (
 	EOSCLP Market = "EOSCLP"
 )
 
+// Split returns the asset and the currency of the Market, and false if it isn't a known Market
+func (m Market) Split() (asset, currency WalletType, ok bool) {
+	asset, ok = MarketAssetMapping[m]
+	return asset, MarketCurrencyMapping[m], ok
+}
+
+// MarketFor returns the Market trading asset against currency, and false if that pair isn't listed
+func MarketFor(asset, currency WalletType) (Market, bool) {
+	for market, a := range MarketAssetMapping {
+		if a == asset && MarketCurrencyMapping[market] == currency {
+			return market, true
+		}
+	}
+	return "", false
+}
+
+// Involves reports whether w is the asset or the currency of the Market
+func (m Market) Involves(w WalletType) bool {
+	return MarketAssetMapping[m] == w || MarketCurrencyMapping[m] == w
+}
+
+// CheckWallets validates that an order of type ot in the Market debits and credits the right
+// Wallets: a BUY debits the currency and credits the asset, and a SELL does the opposite.
+// It returns an error wrapping ErrWalletMismatch otherwise.
+func (m Market) CheckWallets(ot OrderType, debit, credit WalletType) error {
+	asset, currency := MarketAssetMapping[m], MarketCurrencyMapping[m]
+	wantDebit, wantCredit := currency, asset
+	if ot == SELL {
+		wantDebit, wantCredit = asset, currency
+	}
+
+	if debit != wantDebit || credit != wantCredit {
+		return fmt.Errorf("%w: a %s in %s debits %s and credits %s, not %s and %s", ErrWalletMismatch, ot, m, wantDebit, wantCredit, debit, credit)
+	}
+	return nil
+}
+
+// Equal reports whether m and other name the same Market, ignoring case and separators,
+// so "btc-eur" equals BTCEUR
+func (m Market) Equal(other Market) bool {
+	return normalizeMarket(string(m)) == normalizeMarket(string(other))
+}
+
+// NormalizeMarket returns the Market named by s in any case and with any separators, like
+// "btc/eur" or "BTC_EUR", or an error wrapping ErrInvalidMarket if the API doesn't list it
+func NormalizeMarket(s string) (Market, error) {
+	m := Market(normalizeMarket(s))
+	if _, ok := MarketAssetMapping[m]; !ok {
+		return "", fmt.Errorf("market %q: %w", s, ErrInvalidMarket)
+	}
+	return m, nil
+}
+
+// normalizeMarket upper-cases s and drops the separators between its asset and currency
+func normalizeMarket(s string) string {
+	return strings.ToUpper(strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '/', '_', ':', '.', ' ':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s)))
+}
+
+// Timeframe represents the period of a candle in the CryptoMKT API, expressed in minutes
+type Timeframe string
+
+// Timeframe possible values
+const (
+	TF1m  Timeframe = "1"
+	TF5m  Timeframe = "5"
+	TF15m Timeframe = "15"
+	TF1h  Timeframe = "60"
+	TF4h  Timeframe = "240"
+	TF1d  Timeframe = "1440"
+)
+
+// Valid reports whether the Timeframe is one of the values accepted by the API
+func (tf Timeframe) Valid() bool {
+	switch tf {
+	case TF1m, TF5m, TF15m, TF1h, TF4h, TF1d:
+		return true
+	}
+	return false
+}
+
 // MarketResponse is the response of the Markets endpoint
 type MarketResponse struct {
 	Status string
 	Data   []Market
 }
 
+// Sorted returns the Markets of the response in alphabetical order
+func (r MarketResponse) Sorted() []Market {
+	markets := append([]Market(nil), r.Data...)
+	sort.Slice(markets, func(i, j int) bool { return markets[i] < markets[j] })
+	return markets
+}
+
 // Ticker represents a Ticker in the CryptoMKT API
 type Ticker struct {
 	High      string
@@ -130,12 +351,42 @@ type Ticker struct {
 	Market    Market
 }
 
+// Age returns the time elapsed from the Timestamp of the Ticker until now, which is taken as an
+// argument so it can come from the Clock of a Client
+func (t Ticker) Age(now time.Time) time.Duration {
+	return now.Sub(t.Timestamp.Time)
+}
+
+// SpreadPercent returns the difference between Ask and Bid as a percentage of their midpoint
+func (t Ticker) SpreadPercent() (float64, error) {
+	ask, err := parseFloat(t.Ask)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ask %q: %s", t.Ask, err)
+	}
+	bid, err := parseFloat(t.Bid)
+	if err != nil {
+		return 0, fmt.Errorf("invalid bid %q: %s", t.Bid, err)
+	}
+	if ask+bid == 0 {
+		return 0, errors.New("ticker has no prices")
+	}
+	return (ask - bid) / ((ask + bid) / 2) * 100, nil
+}
+
 // TickerResponse is the response of the Ticker endpoint
 type TickerResponse struct {
 	Status string
 	Data   []Ticker
 }
 
+// First returns the first Ticker of the response, and false if there's none
+func (r TickerResponse) First() (Ticker, bool) {
+	if len(r.Data) == 0 {
+		return Ticker{}, false
+	}
+	return r.Data[0], true
+}
+
 // OrderBookOrder represents an Order in the OrderBook
 type OrderBookOrder struct {
 	Timestamp Time
@@ -166,6 +417,31 @@ type TradesResponse struct {
 	Data       []Trade
 }
 
+// Candle represents the prices of one side of a Market during a Timeframe
+type Candle struct {
+	CandleID   FlexInt `json:"candle_id"`
+	OpenPrice  string  `json:"open_price"`
+	HighPrice  string  `json:"hight_price"`
+	ClosePrice string  `json:"close_price"`
+	LowPrice   string  `json:"low_price"`
+	VolumeSum  string  `json:"volume_sum"`
+	CandleDate Time    `json:"candle_date"`
+	TickCount  FlexInt `json:"tick_count"`
+}
+
+// Prices holds the ask and bid Candles of a Market
+type Prices struct {
+	Ask []Candle
+	Bid []Candle
+}
+
+// PricesResponse is the response of the Prices endpoint
+type PricesResponse struct {
+	Status     string
+	Pagination Pagination
+	Data       Prices
+}
+
 // Amount represents the different amounts that compose an Order
 type Amount struct {
 	Original  string
@@ -173,6 +449,16 @@ type Amount struct {
 	Executed  string `json:",omitempty"`
 }
 
+// ExecutedFloat returns the Executed amount, or false if the API omitted it or it isn't a number
+func (a Amount) ExecutedFloat() (float64, bool) {
+	return optionalFloat(a.Executed)
+}
+
+// RemainingFloat returns the Remaining amount, or false if the API omitted it or it isn't a number
+func (a Amount) RemainingFloat() (float64, bool) {
+	return optionalFloat(a.Remaining)
+}
+
 // Order is the representation of an Order in the CryptoMKT API
 type Order struct {
 	Status            string
@@ -187,6 +473,56 @@ type Order struct {
 	UpdatedAt         Time `json:"updated_at"`
 }
 
+// ExecutionPriceFloat returns the ExecutionPrice of the Order, or false if the Order hasn't
+// been executed and the API omitted it
+func (o Order) ExecutionPriceFloat() (float64, bool) {
+	return optionalFloat(o.ExecutionPrice)
+}
+
+// AvgExecutionPriceFloat returns the AvgExecutionPrice of the Order, or false if the Order hasn't
+// been executed and the API omitted it
+func (o Order) AvgExecutionPriceFloat() (float64, bool) {
+	return optionalFloat(o.AvgExecutionPrice)
+}
+
+// FillSummary holds the parsed amounts of an Order and how much of it has been filled
+type FillSummary struct {
+	Original  float64
+	Executed  float64
+	Remaining float64
+	// FillFraction is Executed/Original, from 0 to 1
+	FillFraction float64
+	// AvgPrice is 0 until the Order is at least partially executed
+	AvgPrice float64
+}
+
+// FillSummary parses the amounts of the Order. The optional fields that the API omits, like the
+// executed amount of an untouched Order, are taken as zero, and a missing remaining amount is
+// derived from the original and executed ones.
+func (o Order) FillSummary() (FillSummary, error) {
+	var fs FillSummary
+	var err error
+	if fs.Original, err = parseOptionalFloat(o.Amount.Original); err != nil {
+		return FillSummary{}, fmt.Errorf("invalid original amount %q: %s", o.Amount.Original, err)
+	}
+	if fs.Executed, err = parseOptionalFloat(o.Amount.Executed); err != nil {
+		return FillSummary{}, fmt.Errorf("invalid executed amount %q: %s", o.Amount.Executed, err)
+	}
+	if o.Amount.Remaining == "" {
+		fs.Remaining = fs.Original - fs.Executed
+	} else if fs.Remaining, err = parseFloat(o.Amount.Remaining); err != nil {
+		return FillSummary{}, fmt.Errorf("invalid remaining amount %q: %s", o.Amount.Remaining, err)
+	}
+	if fs.AvgPrice, err = parseOptionalFloat(o.AvgExecutionPrice); err != nil {
+		return FillSummary{}, fmt.Errorf("invalid average execution price %q: %s", o.AvgExecutionPrice, err)
+	}
+
+	if fs.Original != 0 {
+		fs.FillFraction = fs.Executed / fs.Original
+	}
+	return fs, nil
+}
+
 // OrdersResponse is the response of the endpoints ActiveOrders and ExecutedOrders
 type OrdersResponse struct {
 	Status     string
@@ -194,6 +530,61 @@ type OrdersResponse struct {
 	Data       []Order
 }
 
+// FindDuplicates groups the Orders of the response that share market, side and price, and returns
+// the groups with more than one Order, in the order they first appear
+func (r OrdersResponse) FindDuplicates() [][]Order {
+	type key struct {
+		market Market
+		side   OrderType
+		price  string
+	}
+
+	groups := map[key][]Order{}
+	var keys []key
+	for _, o := range r.Data {
+		k := key{market: o.Market, side: o.Type, price: o.Price}
+		// Compare prices by value, so "100" and "100.0" are the same level
+		if price, err := parseFloat(o.Price); err == nil {
+			k.price = strconv.FormatFloat(price, 'f', -1, 64)
+		}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], o)
+	}
+
+	var duplicates [][]Order
+	for _, k := range keys {
+		if len(groups[k]) > 1 {
+			duplicates = append(duplicates, groups[k])
+		}
+	}
+	return duplicates
+}
+
+// StaleOrders returns the Orders of the response created more than olderThan before now
+func (r OrdersResponse) StaleOrders(now time.Time, olderThan time.Duration) []Order {
+	var stale []Order
+	for _, o := range r.Data {
+		if now.Sub(o.CreatedAt.Time) > olderThan {
+			stale = append(stale, o)
+		}
+	}
+	return stale
+}
+
+// CreateOrderRequest describes an Order to create with PlaceOrder.
+// AmountPrecision overrides the decimals of WalletPrecision for the amount when set, and
+// PricePrecision the 4 decimals of the price.
+type CreateOrderRequest struct {
+	Market          Market
+	Type            OrderType
+	Amount          float64
+	Price           float64
+	AmountPrecision *int
+	PricePrecision  *int
+}
+
 // OrderResponse is the response of the endpoints CreateOrder, OrderStatus, and CancelOrder
 type OrderResponse struct {
 	Status string
@@ -207,23 +598,143 @@ type Wallet struct {
 	Balance   string
 }
 
+// AvailableFloat returns the Available amount of the Wallet as a float64
+func (w Wallet) AvailableFloat() (float64, error) {
+	return parseFloat(w.Available)
+}
+
+// BalanceFloat returns the Balance of the Wallet as a float64
+func (w Wallet) BalanceFloat() (float64, error) {
+	return parseFloat(w.Balance)
+}
+
+// Locked returns the amount of the Wallet reserved by active orders, Balance - Available
+func (w Wallet) Locked() (float64, error) {
+	balance, err := w.BalanceFloat()
+	if err != nil {
+		return 0, fmt.Errorf("invalid balance %q: %s", w.Balance, err)
+	}
+	available, err := w.AvailableFloat()
+	if err != nil {
+		return 0, fmt.Errorf("invalid available %q: %s", w.Available, err)
+	}
+	return balance - available, nil
+}
+
 // BalanceResponse is the response of the Balance endpoint
 type BalanceResponse struct {
 	Status string
 	Data   []Wallet
 }
 
+// ByWallet returns the Wallets of the response indexed by WalletType
+func (r BalanceResponse) ByWallet() map[WalletType]Wallet {
+	wallets := make(map[WalletType]Wallet, len(r.Data))
+	for _, w := range r.Data {
+		wallets[w.Wallet] = w
+	}
+	return wallets
+}
+
+// Sorted returns the Wallets of the response in alphabetical order of their WalletType
+func (r BalanceResponse) Sorted() []Wallet {
+	wallets := append([]Wallet(nil), r.Data...)
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i].Wallet < wallets[j].Wallet })
+	return wallets
+}
+
+// Get returns the Wallet of type w, and whether it's present in the response
+func (r BalanceResponse) Get(w WalletType) (Wallet, bool) {
+	for _, wallet := range r.Data {
+		if wallet.Wallet == w {
+			return wallet, true
+		}
+	}
+	return Wallet{}, false
+}
+
+// Transaction represents a movement of a Wallet in the CryptoMKT API, like a trade, a fee,
+// a deposit or a withdrawal
+type Transaction struct {
+	TransactionID string `json:"transaction_id"`
+	Type          FlexInt
+	Amount        string
+	FeePercent    string `json:"fee_percent"`
+	FeeAmount     string `json:"fee_amount"`
+	// Balance is the balance of the Wallet after the Transaction
+	Balance  string
+	Date     Time
+	Hash     string `json:",omitempty"`
+	Address  string `json:",omitempty"`
+	Memo     string `json:",omitempty"`
+	Currency WalletType
+}
+
+// TransactionsResponse is the response of the Transactions endpoint
+type TransactionsResponse struct {
+	Status     string
+	Pagination Pagination
+	Data       []Transaction
+}
+
 type InstantQuote struct {
 	Obtained string
 	Required string
 }
 
+// Rate returns the exchange rate implied by the quote, Required/Obtained. For a BUY quote it is
+// the price in currency paid per unit of the asset, for a SELL quote it is the inverse, the
+// amount of the asset given per unit of currency.
+func (q InstantQuote) Rate() (float64, error) {
+	required, err := parseFloat(q.Required)
+	if err != nil {
+		return 0, fmt.Errorf("invalid required amount %q: %s", q.Required, err)
+	}
+	obtained, err := parseFloat(q.Obtained)
+	if err != nil {
+		return 0, fmt.Errorf("invalid obtained amount %q: %s", q.Obtained, err)
+	}
+	if obtained == 0 {
+		return 0, errors.New("quote has nothing to obtain")
+	}
+	return required / obtained, nil
+}
+
+// Slippage returns the relative difference between the Rate of the quote and referencePrice,
+// which has to be in the same orientation as Rate. Positive values mean that the quote is
+// worse than the reference, for both BUY and SELL quotes.
+func (q InstantQuote) Slippage(referencePrice float64) (float64, error) {
+	if referencePrice == 0 {
+		return 0, errors.New("reference price can't be zero")
+	}
+	rate, err := q.Rate()
+	if err != nil {
+		return 0, err
+	}
+	return (rate - referencePrice) / referencePrice, nil
+}
+
 type InstantGetResponse struct {
 	Status string
-	Data InstantQuote
+	Data   InstantQuote
 }
 
+// InstantCreateResponse is the response of InstantCreate, its Data holds the ID of the created order
 type InstantCreateResponse struct {
 	Status string
-	Data string
-}
\ No newline at end of file
+	Data   string
+}
+
+// OrderID returns the ID of the created instant order. It can be followed up with OrderStatus,
+// which returns ErrOrderNotFound when the order can't be tracked, see InstantCreateAndConfirm.
+func (r InstantCreateResponse) OrderID() string {
+	return strings.TrimSpace(r.Data)
+}
+
+// InstantResult is the outcome of an instant order created with InstantCreateAndConfirm
+type InstantResult struct {
+	Quote   InstantQuote
+	OrderID string
+	// Order is nil when the instant order can't be followed with OrderStatus
+	Order *Order
+}
@@ -0,0 +1,94 @@
+package cryptomkt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeRoundTrip(t *testing.T) {
+	times := []time.Time{
+		time.Date(2019, 2, 14, 10, 0, 0, 123000000, time.UTC),
+		time.Date(2019, 2, 14, 10, 0, 0, 0, time.FixedZone("CLT", -3*60*60)),
+		{},
+	}
+	for _, ts := range times {
+		trade := Trade{MarketTaker: BUY, Timestamp: Time{ts}, Price: "1000", Amount: "0.5", Market: ETHCLP}
+		b, err := json.Marshal(trade)
+		if err != nil {
+			t.Fatalf("Marshal(%v): %v", ts, err)
+		}
+
+		var decoded Trade
+		if err := json.Unmarshal(b, &decoded); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", b, err)
+		}
+		if !decoded.Timestamp.Equal(ts) || decoded.Price != trade.Price || decoded.Market != trade.Market {
+			t.Errorf("Unmarshal(%s) = %+v, want %+v", b, decoded, trade)
+		}
+	}
+}
+
+func TestTimeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+		err  bool
+	}{
+		{`"2019-02-14T10:00:00.123456"`, time.Date(2019, 2, 14, 10, 0, 0, 123456000, time.UTC), false},
+		{`"2019-02-14 10:00:00"`, time.Date(2019, 2, 14, 10, 0, 0, 0, time.UTC), false},
+		{`"2019-02-14T10:00:00.123Z"`, time.Date(2019, 2, 14, 10, 0, 0, 123000000, time.UTC), false},
+		{`null`, time.Time{}, false},
+		{`""`, time.Time{}, false},
+		{`1550138400`, time.Time{}, true},
+		{`"yesterday"`, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		var got Time
+		err := got.UnmarshalJSON([]byte(tt.in))
+		if (err != nil) != tt.err {
+			t.Errorf("UnmarshalJSON(%s) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if !tt.err && !got.Equal(tt.want) {
+			t.Errorf("UnmarshalJSON(%s) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want float64
+		err  bool
+	}{
+		{"1234.56", 1234.56, false},
+		{" 0.5 ", 0.5, false},
+		{"-2.25", -2.25, false},
+		{"1.234,56", 1234.56, false},
+		{"1,234.56", 1234.56, false},
+		{"1.234.567", 1234567, false},
+		{"1,234,567", 1234567, false},
+		{"12,5", 12.5, false},
+		{"0,125", 0.125, false},
+		{"0,001", 0.001, false},
+		{"-0,125", -0.125, false},
+		{"1234,567", 1234.567, false},
+		{"1,2345", 1.2345, false},
+		{"1,000", 0, true},
+		{"123,456", 0, true},
+		{"-1,000", 0, true},
+		{"", 0, true},
+		{"abc", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseFloat(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("parseFloat(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseFloat(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package cryptomkt
+
+import (
+	"context"
+	"fmt"
+)
+
+// PortfolioValue returns the value of the balance of every Wallet expressed in quote. Assets are
+// valued at the bid of their market against quote, and when quote is the asset of the market the
+// balance is converted at its ask. Wallets without a market to quote are skipped, since there's no
+// direct price for them.
+func (c Client) PortfolioValue(ctx context.Context, quote WalletType) (float64, error) {
+	balance, err := c.Balance(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	for _, w := range balance.Data {
+		amount, err := parseFloat(w.Balance)
+		if err != nil {
+			return 0, fmt.Errorf("invalid balance %q of %s: %s", w.Balance, w.Wallet, err)
+		}
+		if amount == 0 {
+			continue
+		}
+		if w.Wallet == quote {
+			total += amount
+			continue
+		}
+
+		if market, ok := MarketFor(w.Wallet, quote); ok {
+			bid, err := c.tickerPrice(ctx, market, func(t Ticker) string { return t.Bid })
+			if err != nil {
+				return 0, err
+			}
+			total += amount * bid
+		} else if market, ok := MarketFor(quote, w.Wallet); ok {
+			ask, err := c.tickerPrice(ctx, market, func(t Ticker) string { return t.Ask })
+			if err != nil {
+				return 0, err
+			}
+			if ask != 0 {
+				total += amount / ask
+			}
+		}
+	}
+	return total, nil
+}
+
+// tickerPrice returns the price picked by field from the Ticker of market
+func (c Client) tickerPrice(ctx context.Context, market Market, field func(Ticker) string) (float64, error) {
+	ticker, err := c.TickerOne(ctx, market)
+	if err != nil {
+		return 0, err
+	}
+
+	price, err := parseFloat(field(*ticker))
+	if err != nil {
+		return 0, fmt.Errorf("invalid price %q of %s: %s", field(*ticker), market, err)
+	}
+	return price, nil
+}
+
+// TradingPower returns how much can be spent right now in market: the available balance of its
+// currency, which funds buys, and of its asset, which is what sells take from. Available already
+// excludes what active orders hold, so it's the room left for new orders.
+func (c Client) TradingPower(ctx context.Context, market Market) (maxBuyQuote, maxSellAsset float64, err error) {
+	asset, ok := MarketAssetMapping[market]
+	if !ok {
+		return 0, 0, fmt.Errorf("market %s: %w", market, ErrInvalidMarket)
+	}
+	currency := MarketCurrencyMapping[market]
+
+	balance, err := c.Balance(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	available := func(w WalletType) (float64, error) {
+		wallet, ok := balance.Get(w)
+		if !ok {
+			return 0, nil
+		}
+		amount, err := wallet.AvailableFloat()
+		if err != nil {
+			return 0, fmt.Errorf("invalid available %q of %s: %s", wallet.Available, w, err)
+		}
+		return amount, nil
+	}
+
+	if maxBuyQuote, err = available(currency); err != nil {
+		return 0, 0, err
+	}
+	if maxSellAsset, err = available(asset); err != nil {
+		return 0, 0, err
+	}
+	return maxBuyQuote, maxSellAsset, nil
+}